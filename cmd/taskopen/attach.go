@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/core"
+)
+
+// newAttachFlagSet registers the flags of the attach command
+func newAttachFlagSet(label, configPath *string) *flagSet {
+	fs := newFlagSet("attach", "taskopen attach [OPTIONS] FILTER... PATH-OR-URL",
+		"Annotate the task selected by FILTER with a file path or URL.")
+	fs.StringVar(label, "label", "", "LABEL", "Prefix the annotation with 'LABEL: '")
+	fs.StringVar(configPath, "config", "", "PATH", "Use this configuration file")
+	return fs
+}
+
+func runAttach(args []string) error {
+	var label, configPath string
+
+	fs := newAttachFlagSet(&label, &configPath)
+	positional, ok, err := fs.ParseOrHelp(args)
+	if !ok {
+		return err
+	}
+
+	if len(positional) < 2 {
+		return fs.usageError("attach requires a filter and a path or URL")
+	}
+	filters := positional[:len(positional)-1]
+	target := positional[len(positional)-1]
+
+	if configPath == "" {
+		configPath, err = config.FindConfigPath()
+		if err != nil {
+			return fmt.Errorf("configuration not found: %w", err)
+		}
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	processor := core.NewTaskProcessor(cfg)
+	return processor.Attach(context.Background(), filters, target, label)
+}
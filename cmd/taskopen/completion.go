@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+	"github.com/johnconnor-sec/taskopen-go/internal/taskwarrior"
+)
+
+// Words completed by the generated scripts
+const (
+	completionCommands       = "normal any batch list attach config diagnostics version completion"
+	completionConfigCommands = "init migrate validate example schema"
+	completionShells         = "bash zsh fish"
+)
+
+// completionData is passed to the completion script templates
+type completionData struct {
+	Commands       string
+	ConfigCommands string
+	Shells         string
+	MainFlags      string
+	ListFlags      string
+	AttachFlags    string
+	FishFlags      string
+}
+
+func runCompletion(args []string) error {
+	fs := newFlagSet("completion", "taskopen completion bash|zsh|fish",
+		"Print a shell completion script.\n\n"+
+			"  bash: source <(taskopen completion bash)\n"+
+			"  zsh:  source <(taskopen completion zsh)\n"+
+			"  fish: taskopen completion fish > ~/.config/fish/completions/taskopen.fish")
+	positional, ok, err := fs.ParseOrHelp(args)
+	if !ok {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return fs.usageError("completion requires exactly one shell: bash, zsh or fish")
+	}
+
+	return writeCompletion(os.Stdout, positional[0])
+}
+
+// writeCompletion writes the completion script for shell to w
+func writeCompletion(w io.Writer, shell string) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	case "fish":
+		script = fishCompletion
+	default:
+		return errors.New(errors.ValidationFailed, fmt.Sprintf("Unsupported shell: %s", shell)).
+			WithSuggestion("Use one of: bash, zsh, fish")
+	}
+
+	mainFlags := newMainFlagSet("", &mainOptions{})
+	listFlags := newMainFlagSet("list", &mainOptions{})
+	attachFlags := newAttachFlagSet(new(string), new(string))
+
+	data := completionData{
+		Commands:       completionCommands,
+		ConfigCommands: completionConfigCommands,
+		Shells:         completionShells,
+		MainFlags:      strings.Join(mainFlags.names(), " "),
+		ListFlags:      strings.Join(listFlags.names(), " "),
+		AttachFlags:    strings.Join(attachFlags.names(), " "),
+		FishFlags: fishFlagLines(`"not __fish_seen_subcommand_from list attach config diagnostics version completion"`, mainFlags) +
+			fishFlagLines(`"__fish_seen_subcommand_from list"`, listFlags) +
+			fishFlagLines(`"__fish_seen_subcommand_from attach"`, attachFlags),
+	}
+
+	return template.Must(template.New(shell).Parse(script)).Execute(w, data)
+}
+
+// fishFlagLines renders a fish complete command for every flag in fs
+func fishFlagLines(condition string, fs *flagSet) string {
+	var b strings.Builder
+	for _, def := range fs.flags {
+		fmt.Fprintf(&b, "complete -c taskopen -n %s -l %s", condition, def.long)
+		if def.short != "" {
+			fmt.Fprintf(&b, " -s %s", def.short)
+		}
+		switch {
+		case def.value == "":
+		case def.long == "config":
+			b.WriteString(" -r -F")
+		case def.long == "action" || def.long == "include" || def.long == "exclude":
+			b.WriteString(` -x -a "(__taskopen_names actions) (__taskopen_names groups)"`)
+		case def.long == "format":
+			b.WriteString(` -x -a "json ndjson tsv"`)
+		default:
+			b.WriteString(" -x")
+		}
+		fmt.Fprintf(&b, " -d '%s'\n", strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(def.help))
+	}
+	return b.String()
+}
+
+// taskwarriorCompletions maps __complete categories to the Taskwarrior
+// helper commands that list them
+var taskwarriorCompletions = map[string]string{
+	"projects": "_projects",
+	"tags":     "_tags",
+	"ids":      "_ids",
+}
+
+// runCompleteNames prints configuration-dependent completion candidates.
+// Taskwarrior values are queried with the configured taskbin and taskargs.
+// It is called by the completion scripts and stays silent on errors.
+func runCompleteNames(args []string) error {
+	if len(args) != 1 {
+		return nil
+	}
+
+	cfg := config.DefaultConfig()
+	if configPath, err := config.FindConfigPath(); err == nil {
+		if loaded, err := config.Load(configPath); err == nil {
+			cfg = loaded
+		}
+	}
+
+	var names []string
+	switch args[0] {
+	case "actions":
+		names = cfg.GetActionNames()
+	case "groups":
+		for name := range cfg.CLI.Groups {
+			names = append(names, name)
+		}
+	case "aliases":
+		for name, expansion := range cfg.CLI.Aliases {
+			if strings.TrimSpace(expansion) != "" {
+				names = append(names, name)
+			}
+		}
+	case "projects", "tags", "ids":
+		client := taskwarrior.NewClient(cfg.General.TaskBin, cfg.General.TaskArgs, 2*time.Second)
+		values, err := client.Helper(context.Background(), taskwarriorCompletions[args[0]])
+		if err != nil {
+			return nil
+		}
+		// Keep Taskwarrior's order, e.g. numeric IDs
+		for _, value := range values {
+			fmt.Println(value)
+		}
+		return nil
+	}
+
+	sort.Strings(names)
+	for _, name := range slices.Compact(names) {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+const bashCompletion = `# bash completion for taskopen
+# Load with: source <(taskopen completion bash)
+
+_taskopen_names() {
+    taskopen __complete "$1" 2>/dev/null
+}
+
+_taskopen() {
+    # Readline splits words at ':', so rebuild the current word from the line
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local cur="${line##*[[:space:]]}"
+    local before="${line%"$cur"}"
+    before="${before%"${before##*[![:space:]]}"}"
+    local prev="${before##*[[:space:]]}"
+    local command="" words=""
+    [[ $COMP_CWORD -gt 1 ]] && command="${COMP_WORDS[1]}"
+
+    case "$prev" in
+        --action|--include|--exclude)
+            words="$(_taskopen_names actions) $(_taskopen_names groups)" ;;
+        --format)
+            words="json ndjson tsv" ;;
+        --config)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return ;;
+        --filter|--sort|--execute|-x|--label)
+            return ;;
+    esac
+
+    if [[ -z "$words" ]]; then
+        case "$command" in
+            config)
+                [[ $COMP_CWORD -eq 2 ]] && COMPREPLY=($(compgen -W "{{.ConfigCommands}}" -- "$cur"))
+                return ;;
+            completion)
+                [[ $COMP_CWORD -eq 2 ]] && COMPREPLY=($(compgen -W "{{.Shells}}" -- "$cur"))
+                return ;;
+            diagnostics|version)
+                return ;;
+        esac
+
+        case "$cur" in
+            project:*)
+                words="$(_taskopen_names projects | sed 's/^/project:/')" ;;
+            +*)
+                words="$(_taskopen_names tags | sed 's/^/+/')" ;;
+            -*)
+                case "$command" in
+                    list) words="{{.ListFlags}}" ;;
+                    attach) words="{{.AttachFlags}}" ;;
+                    *) words="{{.MainFlags}}" ;;
+                esac
+                words="$words $(_taskopen_names tags | sed 's/^/-/')" ;;
+            *)
+                words="project: $(_taskopen_names ids)"
+                [[ $COMP_CWORD -eq 1 ]] && words="{{.Commands}} $(_taskopen_names aliases) $words" ;;
+        esac
+    fi
+
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+
+    # Only the part after the last ':' is replaced by readline
+    if [[ "$cur" == *:* ]]; then
+        local prefix="${cur%"${cur##*:}"}"
+        COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+    fi
+    if [[ ${#COMPREPLY[@]} -eq 1 && "${COMPREPLY[0]}" == *: ]]; then
+        compopt -o nospace
+    fi
+}
+
+complete -F _taskopen taskopen
+`
+
+const zshCompletion = `#compdef taskopen
+# zsh completion for taskopen
+# Load with: source <(taskopen completion zsh), or save as _taskopen in $fpath
+
+_taskopen() {
+  local -a flags
+  local command=""
+  (( CURRENT > 2 )) && command=$words[2]
+
+  case $words[CURRENT-1] in
+    --action|--include|--exclude)
+      compadd -- ${(f)"$(taskopen __complete actions 2>/dev/null)"} ${(f)"$(taskopen __complete groups 2>/dev/null)"}
+      return ;;
+    --format)
+      compadd -- json ndjson tsv
+      return ;;
+    --config)
+      _files
+      return ;;
+    --filter|--sort|--execute|-x|--label)
+      return ;;
+  esac
+
+  case $command in
+    config)
+      (( CURRENT == 3 )) && compadd -- {{.ConfigCommands}}
+      return ;;
+    completion)
+      (( CURRENT == 3 )) && compadd -- {{.Shells}}
+      return ;;
+    diagnostics|version)
+      return ;;
+    list) flags=({{.ListFlags}}) ;;
+    attach) flags=({{.AttachFlags}}) ;;
+    *) flags=({{.MainFlags}}) ;;
+  esac
+
+  case $PREFIX in
+    project:*)
+      compset -P 'project:'
+      compadd -- ${(f)"$(taskopen __complete projects 2>/dev/null)"} ;;
+    +*)
+      compset -P '+'
+      compadd -- ${(f)"$(taskopen __complete tags 2>/dev/null)"} ;;
+    -*)
+      compadd -- $flags
+      compset -P '-'
+      compadd -- ${(f)"$(taskopen __complete tags 2>/dev/null)"} ;;
+    *)
+      (( CURRENT == 2 )) && compadd -- {{.Commands}} ${(f)"$(taskopen __complete aliases 2>/dev/null)"}
+      compadd -S '' -- project:
+      compadd -- ${(f)"$(taskopen __complete ids 2>/dev/null)"} ;;
+  esac
+}
+
+if [[ "$funcstack[1]" == "_taskopen" ]]; then
+  _taskopen "$@"
+else
+  compdef _taskopen taskopen
+fi
+`
+
+const fishCompletion = `# fish completion for taskopen
+# Install with: taskopen completion fish > ~/.config/fish/completions/taskopen.fish
+
+function __taskopen_names
+    taskopen __complete $argv[1] 2>/dev/null
+end
+
+complete -c taskopen -f
+
+# Subcommands and aliases
+complete -c taskopen -n __fish_use_subcommand -a "{{.Commands}}"
+complete -c taskopen -n __fish_use_subcommand -a "(__taskopen_names aliases)"
+complete -c taskopen -n "__fish_seen_subcommand_from config" -a "{{.ConfigCommands}}"
+complete -c taskopen -n "__fish_seen_subcommand_from completion" -a "{{.Shells}}"
+
+# Taskwarrior filters
+set -l __taskopen_filters "not __fish_seen_subcommand_from config diagnostics version completion"
+complete -c taskopen -n $__taskopen_filters -a "(__taskopen_names ids)"
+complete -c taskopen -n $__taskopen_filters -a "project:(__taskopen_names projects)"
+complete -c taskopen -n $__taskopen_filters -a "+(__taskopen_names tags)"
+
+# Flags
+{{.FishFlags}}`
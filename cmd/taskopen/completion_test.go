@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeCompletion(&buf, shell); err != nil {
+				t.Fatalf("writeCompletion(%q) error = %v", shell, err)
+			}
+
+			script := buf.String()
+			for _, want := range []string{"diagnostics", "validate", "no-context", "__complete"} {
+				if !strings.Contains(script, want) {
+					t.Errorf("%s script does not contain %q", shell, want)
+				}
+			}
+			for _, category := range []string{"projects", "tags", "ids"} {
+				if !regexp.MustCompile(`(__complete|_taskopen_names) ` + category).MatchString(script) {
+					t.Errorf("%s script does not complete %s through taskopen", shell, category)
+				}
+			}
+			if strings.Contains(script, "task _") {
+				t.Errorf("%s script runs task directly instead of the configured taskbin", shell)
+			}
+			if strings.Contains(script, "{{") {
+				t.Errorf("%s script contains unexpanded template actions", shell)
+			}
+		})
+	}
+}
+
+func TestWriteCompletion_UnknownShell(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCompletion(&buf, "tcsh"); err == nil {
+		t.Error("writeCompletion(tcsh) error = nil, want error")
+	}
+}
@@ -5,16 +5,26 @@ import (
 	"os"
 
 	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
 )
 
+func printConfigUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  taskopen config <command> [OPTIONS]")
+	fmt.Println()
+	fmt.Println("Config commands:")
+	fmt.Println("  init     - Create configuration interactively")
+	fmt.Println("  migrate  - Migrate INI config to YAML")
+	fmt.Println("  validate - Validate configuration file")
+	fmt.Println("  example  - Show example configuration")
+	fmt.Println("  schema   - Generate JSON schema")
+	fmt.Println()
+	fmt.Println("Run 'taskopen config <command> --help' for command options.")
+}
+
 func runConfigCommand(args []string) error {
-	if len(args) == 0 {
-		fmt.Println("Config commands:")
-		fmt.Println("  init     - Create configuration interactively")
-		fmt.Println("  migrate  - Migrate INI config to YAML")
-		fmt.Println("  validate - Validate configuration file")
-		fmt.Println("  example  - Show example configuration")
-		fmt.Println("  schema   - Generate JSON schema")
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		printConfigUsage()
 		return nil
 	}
 
@@ -22,21 +32,28 @@ func runConfigCommand(args []string) error {
 
 	switch subcommand {
 	case "init":
-		return runConfigInit()
+		return runConfigInit(args[1:])
 	case "migrate":
 		return runConfigMigrate(args[1:])
 	case "validate":
 		return runConfigValidate(args[1:])
 	case "example":
-		return runConfigExample()
+		return runConfigExample(args[1:])
 	case "schema":
 		return runConfigSchema(args[1:])
 	default:
-		return fmt.Errorf("unknown config subcommand: %s", subcommand)
+		return errors.New(errors.ValidationFailed, fmt.Sprintf("Unknown config subcommand: %s", subcommand)).
+			WithSuggestion("Run 'taskopen config --help' for available commands")
 	}
 }
 
-func runConfigInit() error {
+func runConfigInit(args []string) error {
+	fs := newFlagSet("config init", "taskopen config init",
+		"Create a configuration file interactively at the default location.")
+	if _, ok, err := fs.ParseOrHelp(args); !ok {
+		return err
+	}
+
 	configPath, err := config.FindConfigPath()
 	if err != nil {
 		return err
@@ -46,11 +63,18 @@ func runConfigInit() error {
 }
 
 func runConfigMigrate(args []string) error {
+	fs := newFlagSet("config migrate", "taskopen config migrate [INI_PATH YAML_PATH]",
+		"Convert a legacy ~/.taskopenrc into the YAML configuration format.")
+	positional, ok, err := fs.ParseOrHelp(args)
+	if !ok {
+		return err
+	}
+
 	var iniPath, yamlPath string
 
-	if len(args) >= 2 {
-		iniPath = args[0]
-		yamlPath = args[1]
+	if len(positional) >= 2 {
+		iniPath = positional[0]
+		yamlPath = positional[1]
 	} else {
 		// Auto-detect paths
 		homeDir, _ := os.UserHomeDir()
@@ -62,12 +86,18 @@ func runConfigMigrate(args []string) error {
 }
 
 func runConfigValidate(args []string) error {
+	fs := newFlagSet("config validate", "taskopen config validate [PATH]",
+		"Load and validate a configuration file (defaults to the active one).")
+	positional, ok, err := fs.ParseOrHelp(args)
+	if !ok {
+		return err
+	}
+
 	var configPath string
 
-	if len(args) > 0 {
-		configPath = args[0]
+	if len(positional) > 0 {
+		configPath = positional[0]
 	} else {
-		var err error
 		configPath, err = config.FindConfigPath()
 		if err != nil {
 			return err
@@ -77,16 +107,29 @@ func runConfigValidate(args []string) error {
 	return config.ValidateFile(configPath)
 }
 
-func runConfigExample() error {
+func runConfigExample(args []string) error {
+	fs := newFlagSet("config example", "taskopen config example",
+		"Print an annotated example configuration.")
+	if _, ok, err := fs.ParseOrHelp(args); !ok {
+		return err
+	}
+
 	config.ShowConfigExample()
 	return nil
 }
 
 func runConfigSchema(args []string) error {
+	fs := newFlagSet("config schema", "taskopen config schema [OUTPUT_PATH]",
+		"Write the JSON schema for the configuration file (default: taskopen-schema.json).")
+	positional, ok, err := fs.ParseOrHelp(args)
+	if !ok {
+		return err
+	}
+
 	var outputPath string
 
-	if len(args) > 0 {
-		outputPath = args[0]
+	if len(positional) > 0 {
+		outputPath = positional[0]
 	} else {
 		outputPath = "taskopen-schema.json"
 	}
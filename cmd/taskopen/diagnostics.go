@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,9 +13,16 @@ import (
 	"github.com/johnconnor-sec/taskopen-go/internal/exec"
 	"github.com/johnconnor-sec/taskopen-go/internal/output"
 	"github.com/johnconnor-sec/taskopen-go/internal/security"
+	"github.com/johnconnor-sec/taskopen-go/internal/taskwarrior"
 )
 
-func runDiagnostics() error {
+func runDiagnostics(args []string) error {
+	fs := newFlagSet("diagnostics", "taskopen diagnostics",
+		"Check the configuration, Taskwarrior installation and environment.")
+	if _, ok, err := fs.ParseOrHelp(args); !ok {
+		return err
+	}
+
 	formatter := output.NewFormatter(os.Stdout)
 
 	// Configure accessibility if needed
@@ -64,10 +72,12 @@ func runDiagnostics() error {
 
 	// Check taskwarrior
 	taskBin := "task"
+	var taskArgs []string
 	if configErr == nil {
 		// Try to load config to get actual task binary
 		if cfg, err := config.Load(configPath); err == nil {
 			taskBin = cfg.General.TaskBin
+			taskArgs = cfg.General.TaskArgs
 		}
 	}
 
@@ -107,6 +117,9 @@ func runDiagnostics() error {
 		}
 	}
 
+	// Check the taskrc Taskwarrior reads with the configured arguments
+	diagnostics = append(diagnostics, taskrcDiagnostics(taskwarrior.NewClient(taskBin, taskArgs, 5*time.Second), taskArgs)...)
+
 	// Check editor (if configured)
 	if configErr == nil {
 		if cfg, err := config.Load(configPath); err == nil && cfg.General.Editor != "" {
@@ -179,3 +192,66 @@ func runDiagnostics() error {
 
 	return nil
 }
+
+// taskrcDiagnostics reports the taskrc files, data location, UDAs and
+// contexts of the Taskwarrior setup the client uses
+func taskrcDiagnostics(client *taskwarrior.Client, taskArgs []string) []output.DiagnosticInfo {
+	rc, err := client.Taskrc()
+	if err != nil {
+		return []output.DiagnosticInfo{{
+			Component:   "Taskrc",
+			Status:      "⚠ Warning",
+			Details:     map[string]any{"path": taskwarrior.TaskrcPath(taskArgs), "error": err.Error()},
+			Suggestions: []string{"Set TASKRC or add rc:<path> to taskargs", "Check include directives in your taskrc"},
+		}}
+	}
+
+	overrides := make([]string, 0, len(rc.Overrides))
+	for name, value := range rc.Overrides {
+		overrides = append(overrides, fmt.Sprintf("%s=%s", name, value))
+	}
+	slices.Sort(overrides)
+
+	taskrcInfo := output.DiagnosticInfo{
+		Component: "Taskrc",
+		Status:    "✓ Ready",
+		Details: map[string]any{
+			"path":          rc.Path,
+			"files":         len(rc.Files),
+			"data.location": rc.DataLocation(),
+			"overrides":     strings.Join(overrides, " "),
+		},
+	}
+	if len(rc.Warnings) > 0 {
+		taskrcInfo.Status = "⚠ Warning"
+		taskrcInfo.Details["warnings"] = strings.Join(rc.Warnings, "; ")
+		taskrcInfo.Suggestions = []string{"Check the include directives in your taskrc"}
+	}
+	diagnostics := []output.DiagnosticInfo{taskrcInfo}
+
+	udas := []string{}
+	for _, uda := range rc.UDAs() {
+		udas = append(udas, fmt.Sprintf("%s (%s)", uda.Name, uda.Type))
+	}
+	diagnostics = append(diagnostics, output.DiagnosticInfo{
+		Component: "Taskwarrior UDAs",
+		Status:    "✓ Ready",
+		Details:   map[string]any{"count": len(udas), "udas": strings.Join(udas, ", ")},
+	})
+
+	contexts := []string{}
+	for _, taskContext := range rc.Contexts() {
+		contexts = append(contexts, taskContext.Name)
+	}
+	active := rc.ActiveContext()
+	if active == "" {
+		active = "none"
+	}
+	diagnostics = append(diagnostics, output.DiagnosticInfo{
+		Component: "Taskwarrior Contexts",
+		Status:    "✓ Ready",
+		Details:   map[string]any{"active": active, "defined": strings.Join(contexts, ", ")},
+	})
+
+	return diagnostics
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+)
+
+// errHelp is returned by flagSet.Parse when -h or --help was given
+var errHelp = stderrors.New("help requested")
+
+// flagDef describes a single command-line flag
+type flagDef struct {
+	long  string
+	short string
+	value string // placeholder for the flag argument, empty for boolean flags
+	help  string
+	set   func(value string) error
+}
+
+// flagSet is a small getopt-style parser shared by the main command and its
+// subcommands. It supports --long, --long=value, --long value, -s value and
+// the -- terminator. Flags and positional arguments may be interleaved.
+//
+// Only --long words and single-letter -s words are flags. Longer single-dash
+// words such as -home are kept as positional arguments, because "-tag" is
+// Taskwarrior filter syntax; short flags therefore cannot be combined. An
+// unknown single-letter flag is an error, so a one-letter tag exclusion must
+// follow --.
+type flagSet struct {
+	name        string
+	synopsis    string
+	description string
+	flags       []*flagDef
+
+	// firstArg is the index in the parsed args of the first positional argument, or -1
+	firstArg int
+}
+
+// newFlagSet creates a flag set for the named command
+func newFlagSet(name, synopsis, description string) *flagSet {
+	return &flagSet{name: name, synopsis: synopsis, description: description, firstArg: -1}
+}
+
+// Func registers a flag handled by fn. Flags with a non-empty value
+// placeholder take an argument.
+func (fs *flagSet) Func(long, short, value, help string, fn func(string) error) {
+	fs.flags = append(fs.flags, &flagDef{long: long, short: short, value: value, help: help, set: fn})
+}
+
+// BoolVar registers a boolean flag that sets *p to target when given
+func (fs *flagSet) BoolVar(p *bool, target bool, long, short, help string) {
+	fs.Func(long, short, "", help, func(string) error {
+		*p = target
+		return nil
+	})
+}
+
+// StringVar registers a flag whose argument is stored in *p
+func (fs *flagSet) StringVar(p *string, long, short, value, help string) {
+	fs.Func(long, short, value, help, func(v string) error {
+		*p = v
+		return nil
+	})
+}
+
+// StringsVar registers a repeatable flag whose arguments are appended to *p
+func (fs *flagSet) StringsVar(p *[]string, long, short, value, help string) {
+	fs.Func(long, short, value, help, func(v string) error {
+		*p = append(*p, v)
+		return nil
+	})
+}
+
+// Parse processes args and returns the positional arguments
+func (fs *flagSet) Parse(args []string) ([]string, error) {
+	var positional []string
+	fs.firstArg = -1
+
+	addPositional := func(i int, arg string) {
+		if fs.firstArg < 0 {
+			fs.firstArg = i
+		}
+		positional = append(positional, arg)
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			for j := i + 1; j < len(args); j++ {
+				addPositional(j, args[j])
+			}
+			return positional, nil
+
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			if name == "help" {
+				return nil, errHelp
+			}
+			def := fs.lookupLong(name)
+			if def == nil {
+				return nil, fs.unknownFlagError("--" + name)
+			}
+			if def.value == "" {
+				if hasValue {
+					return nil, fs.usageError(fmt.Sprintf("flag --%s does not take a value", name))
+				}
+			} else if !hasValue {
+				if i+1 >= len(args) {
+					return nil, fs.usageError(fmt.Sprintf("flag --%s requires a value", name))
+				}
+				i++
+				value = args[i]
+			}
+			if err := def.set(value); err != nil {
+				return nil, err
+			}
+
+		case len(arg) == 2 && arg[0] == '-':
+			consumed, err := fs.parseShort(arg[1:], args, i)
+			if err != nil {
+				return nil, err
+			}
+			i += consumed
+
+		default:
+			addPositional(i, arg)
+		}
+	}
+
+	return positional, nil
+}
+
+// ParseOrHelp parses args and prints the help text when it was requested.
+// The returned bool reports whether the command should continue.
+func (fs *flagSet) ParseOrHelp(args []string) ([]string, bool, error) {
+	positional, err := fs.Parse(args)
+	if err == errHelp {
+		fs.PrintHelp(os.Stdout)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return positional, true, nil
+}
+
+// parseShort applies the short flag name and returns how many extra
+// arguments were consumed
+func (fs *flagSet) parseShort(name string, args []string, index int) (int, error) {
+	if name == "h" {
+		return 0, errHelp
+	}
+
+	def := fs.lookupShort(name)
+	if def == nil {
+		return 0, fs.unknownFlagError("-" + name)
+	}
+	if def.value == "" {
+		return 0, def.set("")
+	}
+
+	if index+1 >= len(args) {
+		return 0, fs.usageError(fmt.Sprintf("flag -%s requires a value", name))
+	}
+	return 1, def.set(args[index+1])
+}
+
+func (fs *flagSet) lookupLong(name string) *flagDef {
+	for _, def := range fs.flags {
+		if def.long == name {
+			return def
+		}
+	}
+	return nil
+}
+
+func (fs *flagSet) lookupShort(name string) *flagDef {
+	for _, def := range fs.flags {
+		if def.short != "" && def.short == name {
+			return def
+		}
+	}
+	return nil
+}
+
+// unknownFlagError builds an error with "did you mean" suggestions
+func (fs *flagSet) unknownFlagError(flag string) error {
+	err := errors.New(errors.ValidationFailed, fmt.Sprintf("Unknown flag: %s", flag))
+
+	if suggestions := fs.suggest(strings.TrimLeft(flag, "-")); len(suggestions) > 0 {
+		err = err.WithSuggestion(fmt.Sprintf("Did you mean %s?", strings.Join(suggestions, " or ")))
+	}
+	if !strings.HasPrefix(flag, "--") {
+		err = err.WithSuggestion(fmt.Sprintf("Put Taskwarrior filters such as %s after --", flag))
+	}
+	return err.WithSuggestion(fs.helpHint())
+}
+
+// usageError builds an error for malformed flag usage
+func (fs *flagSet) usageError(message string) error {
+	return errors.New(errors.ValidationFailed, message).
+		WithSuggestion(fs.helpHint())
+}
+
+// helpHint tells the user how to get help for this command
+func (fs *flagSet) helpHint() string {
+	command := "taskopen"
+	if fs.name != "" {
+		command += " " + fs.name
+	}
+	return fmt.Sprintf("Run '%s --help' for usage", command)
+}
+
+// suggest returns long flags similar to name
+func (fs *flagSet) suggest(name string) []string {
+	type candidate struct {
+		flag     string
+		distance int
+	}
+	var candidates []candidate
+
+	for _, def := range fs.flags {
+		distance := levenshtein(name, def.long)
+		if distance <= 2 || (len(name) >= 3 && strings.HasPrefix(def.long, name)) {
+			candidates = append(candidates, candidate{"--" + def.long, distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var suggestions []string
+	for i, c := range candidates {
+		if i == 3 {
+			break
+		}
+		suggestions = append(suggestions, c.flag)
+	}
+	return suggestions
+}
+
+// names returns every flag spelling, for shell completion
+func (fs *flagSet) names() []string {
+	var names []string
+	for _, def := range fs.flags {
+		names = append(names, "--"+def.long)
+		if def.short != "" {
+			names = append(names, "-"+def.short)
+		}
+	}
+	return append(names, "--help")
+}
+
+// PrintHelp writes the usage text for this command
+func (fs *flagSet) PrintHelp(w io.Writer) {
+	fmt.Fprintf(w, "Usage:\n  %s\n", fs.synopsis)
+	if fs.description != "" {
+		fmt.Fprintf(w, "\n%s\n", fs.description)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Options:")
+	for _, def := range fs.flags {
+		fmt.Fprintf(w, "  %-26s %s\n", def.label(), def.help)
+	}
+	fmt.Fprintf(w, "  %-26s %s\n", "-h, --help", "Show this help message")
+}
+
+// label formats the flag names for help output
+func (d *flagDef) label() string {
+	label := "--" + d.long
+	if d.short != "" {
+		label = "-" + d.short + ", " + label
+	} else {
+		label = "    " + label
+	}
+	if d.value != "" {
+		label += " " + d.value
+	}
+	return label
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMainArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		interactive bool
+		single      bool
+		configPath  string
+		filters     []string
+		positional  []string
+	}{
+		{
+			name:        "defaults",
+			args:        nil,
+			interactive: true,
+			single:      true,
+		},
+		{
+			name:        "flag after filter is applied once",
+			args:        []string{"project:work", "-m", "+bug"},
+			interactive: true,
+			single:      false,
+			positional:  []string{"project:work", "+bug"},
+		},
+		{
+			name:        "dash words made of short flag letters are filters",
+			args:        []string{"-ms", "-hi", "-iv", "+bug"},
+			interactive: true,
+			single:      true,
+			positional:  []string{"-ms", "-hi", "-iv", "+bug"},
+		},
+		{
+			name:        "value flags in both forms",
+			args:        []string{"--config", "/tmp/a.yml", "--filter=+work", "--filter", "due:today"},
+			interactive: true,
+			single:      true,
+			configPath:  "/tmp/a.yml",
+			filters:     []string{"+work", "due:today"},
+		},
+		{
+			name:        "double dash ends flag parsing",
+			args:        []string{"--no-interactive", "--", "-m", "--debug"},
+			interactive: false,
+			single:      true,
+			positional:  []string{"-m", "--debug"},
+		},
+		{
+			name:        "taskwarrior tag exclusion stays a filter",
+			args:        []string{"-home", "-m"},
+			interactive: true,
+			single:      false,
+			positional:  []string{"-home"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, _, positional, err := parseMainArgs("", tt.args)
+			if err != nil {
+				t.Fatalf("parseMainArgs(%q) error = %v", tt.args, err)
+			}
+			if opts.interactive != tt.interactive {
+				t.Errorf("interactive = %v, want %v", opts.interactive, tt.interactive)
+			}
+			if opts.single != tt.single {
+				t.Errorf("single = %v, want %v", opts.single, tt.single)
+			}
+			if opts.configPath != tt.configPath {
+				t.Errorf("configPath = %q, want %q", opts.configPath, tt.configPath)
+			}
+			if !reflect.DeepEqual(opts.filters, tt.filters) {
+				t.Errorf("filters = %q, want %q", opts.filters, tt.filters)
+			}
+			if !reflect.DeepEqual(positional, tt.positional) {
+				t.Errorf("positional = %q, want %q", positional, tt.positional)
+			}
+		})
+	}
+}
+
+func TestParseMainArgs_FirstArgIndex(t *testing.T) {
+	_, fs, _, err := parseMainArgs("", []string{"--sort", "urgency-", "-i", "work", "+bug"})
+	if err != nil {
+		t.Fatalf("parseMainArgs() error = %v", err)
+	}
+	if fs.firstArg != 3 {
+		t.Errorf("firstArg = %d, want 3", fs.firstArg)
+	}
+}
+
+func TestParseMainArgs_Execute(t *testing.T) {
+	opts, _, positional, err := parseMainArgs("", []string{"-x", "ls -l $FILE", "-xmas"})
+	if err != nil {
+		t.Fatalf("parseMainArgs() error = %v", err)
+	}
+	if opts.execute != "ls -l $FILE" {
+		t.Errorf("execute = %q, want %q", opts.execute, "ls -l $FILE")
+	}
+	if !reflect.DeepEqual(positional, []string{"-xmas"}) {
+		t.Errorf("positional = %q, want [-xmas]", positional)
+	}
+
+	if _, _, _, err := parseMainArgs("list", []string{"--execute", "ls"}); err == nil {
+		t.Error("parseMainArgs(list --execute) should reject --execute")
+	}
+}
+
+func TestParseMainArgs_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"unknown flag suggests close match", []string{"--fitler", "x"}, "Did you mean --filter?"},
+		{"missing value", []string{"--config"}, "requires a value"},
+		{"unexpected value", []string{"--debug=yes"}, "does not take a value"},
+		{"unknown short flag", []string{"-z"}, "Unknown flag: -z"},
+		{"unknown short flag suggests --", []string{"+bug", "-z"}, "Put Taskwarrior filters such as -z after --"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := parseMainArgs("", tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseMainArgs(%q) error = %v, want error containing %q", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseMainArgs_Help(t *testing.T) {
+	for _, args := range [][]string{{"--help"}, {"-h"}, {"+bug", "-m", "-h"}} {
+		if _, _, _, err := parseMainArgs("", args); err != errHelp {
+			t.Errorf("parseMainArgs(%q) error = %v, want errHelp", args, err)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"sort", "sort", 0},
+		{"srot", "sort", 2},
+		{"debg", "debug", 1},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
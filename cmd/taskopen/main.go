@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
+	"strings"
 
 	"github.com/johnconnor-sec/taskopen-go/internal/config"
 	"github.com/johnconnor-sec/taskopen-go/internal/core"
@@ -30,24 +32,37 @@ func main() {
 func run() error {
 	args := os.Args[1:]
 
-	// Handle version flag
-	if len(args) > 0 && (args[0] == "--version" || args[0] == "-v" || args[0] == "version") {
-		printVersion()
-		return nil
+	if len(args) > 0 {
+		switch args[0] {
+		case "version":
+			return runVersion(args[1:])
+		case "diagnostics":
+			return runDiagnostics(args[1:])
+		case "config":
+			return runConfigCommand(args[1:])
+		case "list":
+			return runList(args[1:])
+		case "attach":
+			return runAttach(args[1:])
+		case "completion":
+			return runCompletion(args[1:])
+		case "__complete":
+			return runCompleteNames(args[1:])
+		}
 	}
 
-	// Handle diagnostics command
-	if len(args) > 0 && args[0] == "diagnostics" {
-		return runDiagnostics()
-	}
+	// Main taskopen functionality - run the core application
+	return runTaskOpen(args)
+}
 
-	// Handle config commands
-	if len(args) > 0 && args[0] == "config" {
-		return runConfigCommand(args[1:])
+func runVersion(args []string) error {
+	fs := newFlagSet("version", "taskopen version", "Show version and build information.")
+	if _, ok, err := fs.ParseOrHelp(args); !ok {
+		return err
 	}
 
-	// Main taskopen functionality - run the core application
-	return runTaskOpen(args)
+	printVersion()
+	return nil
 }
 
 func printVersion() {
@@ -66,33 +81,100 @@ func printVersion() {
 	table.Print()
 }
 
-func printUsage() {
+// mainOptions holds the parsed flags of the main command
+type mainOptions struct {
+	interactive bool
+	single      bool
+	version     bool
+	debug       bool
+	noContext   bool
+	configPath  string
+	sort        string
+	action      string
+	execute     string
+	format      string
+	include     []string
+	exclude     []string
+	filters     []string
+}
+
+// newMainFlagSet registers the flags of the main command, or of a
+// task-processing subcommand such as list, on opts
+func newMainFlagSet(command string, opts *mainOptions) *flagSet {
+	synopsis := "taskopen [normal|any|batch] [OPTIONS] [FILTERS...]"
+	description := ""
+	if command == "list" {
+		synopsis = "taskopen list [normal|any|batch] [OPTIONS] [FILTERS...]"
+		description = "Print all matching actionables in a machine-readable format."
+	}
+	fs := newFlagSet(command, synopsis, description)
+
+	if command == "" {
+		fs.BoolVar(&opts.interactive, true, "interactive", "i", "Enable interactive menu (default)")
+		fs.BoolVar(&opts.interactive, false, "no-interactive", "", "Disable interactive menu, list matches")
+		fs.BoolVar(&opts.interactive, false, "batch", "", "Same as --no-interactive")
+		fs.StringVar(&opts.execute, "execute", "x", "CMD", "Run CMD instead of the matched action's command")
+	}
+	fs.BoolVar(&opts.single, true, "single", "s", "Use the first matching action per annotation (default)")
+	fs.BoolVar(&opts.single, false, "multiple", "m", "Use every matching action per annotation")
+	fs.StringsVar(&opts.filters, "filter", "", "FILTER", "Add a taskwarrior filter (repeatable)")
+	fs.StringVar(&opts.action, "action", "", "NAME", "Only use the named action")
+	fs.StringsVar(&opts.include, "include", "", "NAMES", "Only use these actions or groups (comma-separated)")
+	fs.StringsVar(&opts.exclude, "exclude", "", "NAMES", "Skip these actions or groups (comma-separated)")
+	fs.StringVar(&opts.sort, "sort", "", "KEYS", "Override the sort order, e.g. urgency-,annot")
+	fs.BoolVar(&opts.noContext, true, "no-context", "", "Ignore the active Taskwarrior context")
+	fs.StringVar(&opts.configPath, "config", "", "PATH", "Use this configuration file")
+	fs.BoolVar(&opts.debug, true, "debug", "", "Enable debug logging")
+
+	switch command {
+	case "":
+		fs.BoolVar(&opts.version, true, "version", "v", "Show version information")
+	case "list":
+		fs.StringVar(&opts.format, "format", "", "FORMAT", "Output format: json, ndjson or tsv (default json)")
+	}
+
+	return fs
+}
+
+// parseMainArgs parses the command line of a task-processing command into fresh options
+func parseMainArgs(command string, args []string) (*mainOptions, *flagSet, []string, error) {
+	opts := &mainOptions{interactive: true, single: true}
+	fs := newMainFlagSet(command, opts)
+	positional, err := fs.Parse(args)
+	return opts, fs, positional, err
+}
+
+func printUsage(fs *flagSet) {
 	formatter := output.NewFormatter(os.Stdout)
 
 	formatter.Header("Taskopen - Interactive Task Annotation Opener")
 
-	fmt.Println("Usage:")
-	fmt.Println("  taskopen [OPTIONS] [FILTERS...]")
-	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  -i, --interactive      Enable interactive menu (default)")
-	fmt.Println("  --no-interactive       Disable interactive menu, use first match")
-	fmt.Println("  --batch                Same as --no-interactive")
-	fmt.Println("  -s, --single           Process single task only (default)")
-	fmt.Println("  -m, --multiple         Process multiple tasks")
-	fmt.Println("  -v, --version          Show version information")
-	fmt.Println("  -h, --help             Show this help message")
+	fs.PrintHelp(os.Stdout)
 	fmt.Println()
 	fmt.Println("Commands:")
+	fmt.Println("  taskopen normal        Offer the first matching action per annotation (default)")
+	fmt.Println("  taskopen any           Offer every matching action per annotation")
+	fmt.Println("  taskopen batch         Run the first matching action of every annotation")
+	fmt.Println("  taskopen list          Print actionables as JSON, NDJSON or TSV")
+	fmt.Println("  taskopen attach        Annotate a task with a file or URL")
 	fmt.Println("  taskopen config init   Initialize configuration")
 	fmt.Println("  taskopen diagnostics   Run system diagnostics")
+	fmt.Println("  taskopen completion    Print a bash, zsh or fish completion script")
 	fmt.Println("  taskopen version       Show version information")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  taskopen                           # Interactive menu for all tasks")
 	fmt.Println("  taskopen project:work              # Interactive menu for work project")
-	fmt.Println("  taskopen --no-interactive urgent   # Execute first urgent task action")
-	fmt.Println("  taskopen -m project:home           # Process multiple home project tasks")
+	fmt.Println("  taskopen --no-interactive urgent   # List matching actions for urgent tasks")
+	fmt.Println("  taskopen -m project:home           # Offer every action for home project tasks")
+	fmt.Println("  taskopen any +bug                  # Choose from all actions for bug tasks")
+	fmt.Println("  taskopen batch +READY              # Open every annotation of ready tasks")
+	fmt.Println("  taskopen --include=notes +work     # Only offer actions from the notes group")
+	fmt.Println("  taskopen --action url -- -home     # Use '--' before filters starting with '-'")
+	fmt.Println("  taskopen -x 'ls -l $FILE' +docs    # Run a custom command on the chosen file")
+	fmt.Println()
+	fmt.Println("Aliases defined under cli.aliases expand to their configured arguments,")
+	fmt.Println("e.g. 'taskopen work' with 'work: any project:work'.")
 	fmt.Println()
 	fmt.Println("Interactive Menu Controls:")
 	fmt.Println("  j/k or ↑/↓    Navigate up/down")
@@ -100,52 +182,168 @@ func printUsage() {
 	fmt.Println("  /             Search actions")
 	fmt.Println("  ?             Show help")
 	fmt.Println("  q or Esc      Cancel/quit")
-	fmt.Println("  Space         Multi-select (when available)")
+	fmt.Println("  Space         Mark items to run several at once")
 }
 
-func runTaskOpen(args []string) error {
-	// Parse command-line flags
-	interactive := true // Default to interactive mode
-	single := true      // Default to single mode
-	var filters []string
-
-	// Simple flag parsing
-	for i, arg := range args {
-		switch arg {
-		case "--interactive", "-i":
-			interactive = true
-		case "--no-interactive", "--batch":
-			interactive = false
-		case "--single", "-s":
-			single = true
-		case "--multiple", "-m":
-			single = false
-		case "--help", "-h":
-			printUsage()
-			return nil
-		default:
-			// All remaining arguments are filters
-			filters = args[i:]
-			break
+// taskRun is a fully resolved invocation of a task-processing command
+type taskRun struct {
+	cfg     *config.Config
+	opts    *mainOptions
+	process core.ProcessOptions
+}
+
+// prepareTaskRun parses args, loads the configuration and resolves aliases,
+// modes and action selections. It returns a nil taskRun when the command has
+// already been handled, e.g. by printing help.
+func prepareTaskRun(command string, args []string) (*taskRun, error) {
+	opts, fs, positional, err := parseMainArgs(command, args)
+	if err != nil {
+		if err != errHelp {
+			return nil, err
+		}
+		if command == "" {
+			printUsage(fs)
+		} else {
+			fs.PrintHelp(os.Stdout)
 		}
+		return nil, nil
+	}
+
+	if opts.version {
+		printVersion()
+		return nil, nil
 	}
 
 	// Load configuration
-	configPath, err := config.FindConfigPath()
-	if err != nil {
-		return fmt.Errorf("configuration not found: %w", err)
+	configPath := opts.configPath
+	if configPath == "" {
+		configPath, err = config.FindConfigPath()
+		if err != nil {
+			return nil, fmt.Errorf("configuration not found: %w", err)
+		}
 	}
 
 	cfg, err := config.Load(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Expand a CLI alias in place of the first positional argument and
+	// parse the resulting command line again
+	if fs.firstArg >= 0 {
+		expanded, err := cfg.ExpandAlias(args[fs.firstArg:])
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Equal(expanded, args[fs.firstArg:]) {
+			args = append(slices.Clone(args[:fs.firstArg]), expanded...)
+			if opts, _, positional, err = parseMainArgs(command, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Handle mode subcommands (normal, any, batch)
+	var mode core.Mode
+	if len(positional) > 0 && core.IsMode(positional[0]) {
+		mode, _ = core.ParseMode(positional[0])
+		positional = positional[1:]
+	}
+
+	// Resolve action groups into action names
+	include, err := cfg.ResolveActionNames(splitNames(opts.include))
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := cfg.ResolveActionNames(splitNames(opts.exclude))
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.action != "" {
+		if _, exists := cfg.GetAction(opts.action); !exists {
+			return nil, errors.ActionNotFoundError(opts.action).
+				WithSuggestion(fmt.Sprintf("Available actions: %s", strings.Join(cfg.GetActionNames(), ", ")))
+		}
+		include = []string{opts.action}
+	}
+
+	// Apply command-line overrides to the configuration
+	if opts.sort != "" {
+		cfg.General.Sort = opts.sort
+	}
+	if opts.debug {
+		cfg.General.Debug = true
+	}
+
+	// Fall back to the configured default subcommand
+	if mode == "" {
+		mode, err = core.ParseMode(cfg.CLI.DefaultSubcommand)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ConfigInvalid, "Invalid default subcommand").
+				WithDetails(fmt.Sprintf("cli.default_subcommand: %s", cfg.CLI.DefaultSubcommand)).
+				WithSuggestion("Use one of: normal, any, batch")
+		}
+	}
+
+	return &taskRun{
+		cfg:  cfg,
+		opts: opts,
+		process: core.ProcessOptions{
+			// The --filter values and remaining arguments are all filters
+			Filters:     append(opts.filters, positional...),
+			Mode:        mode,
+			Single:      opts.single,
+			Interactive: opts.interactive,
+			Include:     include,
+			Exclude:     exclude,
+			NoContext:   opts.noContext,
+			Execute:     opts.execute,
+		},
+	}, nil
+}
+
+func runTaskOpen(args []string) error {
+	run, err := prepareTaskRun("", args)
+	if err != nil || run == nil {
+		return err
 	}
 
 	// Create task processor
-	processor := core.NewTaskProcessor(cfg)
+	processor := core.NewTaskProcessor(run.cfg)
+
+	return processor.ProcessTasks(context.Background(), run.process)
+}
 
-	// Process tasks with the provided arguments as filters
-	return processor.ProcessTasks(context.Background(), filters, single, interactive)
+func runList(args []string) error {
+	run, err := prepareTaskRun("list", args)
+	if err != nil || run == nil {
+		return err
+	}
+
+	format, err := core.ParseListFormat(run.opts.format)
+	if err != nil {
+		return errors.Wrap(err, errors.ValidationFailed, "Invalid list format").
+			WithSuggestion("Use one of: json, ndjson, tsv")
+	}
+
+	processor := core.NewTaskProcessor(run.cfg)
+
+	actionables, _, err := processor.CollectActionables(context.Background(), run.process)
+	if err != nil {
+		return err
+	}
+
+	return core.WriteActionables(os.Stdout, processor.ActionableRecords(actionables), format)
+}
+
+// splitNames splits comma-separated flag values into individual names
+func splitNames(values []string) []string {
+	var names []string
+	for _, value := range values {
+		names = append(names, strings.Split(value, ",")...)
+	}
+	return names
 }
 
 func handleError(err error) {
@@ -0,0 +1,91 @@
+// Package config - CLI alias and action group resolution
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+	"github.com/johnconnor-sec/taskopen-go/internal/shellwords"
+)
+
+// maxAliasDepth limits how many times aliases may expand into other aliases.
+const maxAliasDepth = 10
+
+// ExpandAlias replaces a leading alias in args with its configured arguments.
+// Aliases may expand to other aliases; expansion stops at the first argument
+// that is not an alias or has an empty definition.
+func (c *Config) ExpandAlias(args []string) ([]string, error) {
+	seen := make(map[string]bool)
+
+	for depth := 0; len(args) > 0; depth++ {
+		value, ok := c.CLI.Aliases[args[0]]
+		if !ok || strings.TrimSpace(value) == "" {
+			return args, nil
+		}
+
+		if seen[args[0]] || depth >= maxAliasDepth {
+			return nil, errors.New(errors.ConfigInvalid, "Recursive CLI alias").
+				WithDetails(fmt.Sprintf("Alias: %s", args[0])).
+				WithSuggestion("Check the cli.aliases section of your configuration")
+		}
+		seen[args[0]] = true
+
+		expanded, err := shellwords.Split(value)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ConfigInvalid, "Invalid CLI alias").
+				WithDetails(fmt.Sprintf("alias.%s = %s", args[0], value))
+		}
+		args = append(expanded, args[1:]...)
+	}
+
+	return args, nil
+}
+
+// GroupMembers returns the action names listed in the named group.
+func (c *Config) GroupMembers(group string) ([]string, bool) {
+	value, ok := c.CLI.Groups[group]
+	if !ok {
+		return nil, false
+	}
+
+	var members []string
+	for name := range strings.SplitSeq(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			members = append(members, name)
+		}
+	}
+	return members, true
+}
+
+// ResolveActionNames expands group names into their member actions and
+// verifies that every resulting name refers to a configured action.
+func (c *Config) ResolveActionNames(names []string) ([]string, error) {
+	var resolved []string
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		candidates := []string{name}
+		if members, ok := c.GroupMembers(name); ok {
+			candidates = members
+		}
+
+		for _, candidate := range candidates {
+			if _, exists := c.GetAction(candidate); !exists {
+				return nil, errors.New(errors.ActionNotFound, "Unknown action or group").
+					WithDetails(fmt.Sprintf("Name: %s", candidate)).
+					WithSuggestion(fmt.Sprintf("Available actions: %s", strings.Join(c.GetActionNames(), ", ")))
+			}
+			if !slices.Contains(resolved, candidate) {
+				resolved = append(resolved, candidate)
+			}
+		}
+	}
+
+	return resolved, nil
+}
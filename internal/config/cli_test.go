@@ -0,0 +1,70 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandAlias(t *testing.T) {
+	config := DefaultConfig()
+	config.CLI.Aliases["work"] = `any project:work "description:weekly review"`
+	config.CLI.Aliases["w"] = "work +next"
+	config.CLI.Aliases["loop"] = "loop"
+	config.CLI.Aliases["path"] = `"description:C:\temp\x" 'a\b'`
+
+	tests := []struct {
+		name      string
+		args      []string
+		want      []string
+		wantError bool
+	}{
+		{"no args", nil, nil, false},
+		{"not an alias", []string{"+bug"}, []string{"+bug"}, false},
+		{"empty alias is kept", []string{"batch", "+bug"}, []string{"batch", "+bug"}, false},
+		{"alias with quotes", []string{"work"}, []string{"any", "project:work", "description:weekly review"}, false},
+		{"alias keeps trailing args", []string{"work", "+urgent"}, []string{"any", "project:work", "description:weekly review", "+urgent"}, false},
+		{"nested alias", []string{"w"}, []string{"any", "project:work", "description:weekly review", "+next"}, false},
+		{"backslashes split like sh", []string{"path"}, []string{`description:C:\temp\x`, `a\b`}, false},
+		{"recursive alias", []string{"loop"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := config.ExpandAlias(tt.args)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ExpandAlias() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandAlias() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveActionNames(t *testing.T) {
+	config := DefaultConfig()
+	config.CLI.Groups["open"] = "files, url"
+
+	got, err := config.ResolveActionNames([]string{"open", "notes", "url"})
+	if err != nil {
+		t.Fatalf("ResolveActionNames() error = %v", err)
+	}
+	want := []string{"files", "url", "notes"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveActionNames() = %q, want %q", got, want)
+	}
+
+	if _, err := config.ResolveActionNames([]string{"missing"}); err == nil {
+		t.Error("ResolveActionNames() should reject unknown names")
+	}
+}
+
+func TestConfigValidation_GroupMembers(t *testing.T) {
+	config := DefaultConfig()
+	config.CLI.Groups["broken"] = "files,nonexistent"
+
+	err := config.Validate()
+	if err == nil || !containsString(err.Error(), "group refers to an unknown action") {
+		t.Errorf("Validate() error = %v, want unknown group member error", err)
+	}
+}
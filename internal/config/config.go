@@ -47,6 +47,9 @@ type GeneralConfig struct {
 	// Hook for tasks without annotations
 	NoAnnotationHook string `yaml:"no_annotation_hook" json:"no_annotation_hook" default:"task $ID annotate"`
 
+	// When the no-annotation hook runs: single, each or prompt
+	NoAnnotationMode string `yaml:"no_annotation_mode" json:"no_annotation_mode" default:"single"`
+
 	// Default task sort order
 	Sort string `yaml:"sort" json:"sort" default:"urgency-,annot"`
 
@@ -55,8 +58,40 @@ type GeneralConfig struct {
 
 	// Debug mode
 	Debug bool `yaml:"debug" json:"debug"`
+
+	// How several selected actions are executed: sequential or concurrent
+	BulkExecution string `yaml:"bulk_execution" json:"bulk_execution" default:"sequential"`
+
+	// Maximum number of filter commands run at the same time
+	FilterWorkers int `yaml:"filter_workers" json:"filter_workers" default:"4"`
+
+	// Run each distinct expanded filter command only once per run
+	FilterCache bool `yaml:"filter_cache" json:"filter_cache"`
+
+	// Try the next action matching the same text when a command fails
+	FallbackToNextMatch bool `yaml:"fallback_to_next_match" json:"fallback_to_next_match"`
+
+	// Merge actionables of several tasks whose expanded commands are identical
+	GroupDuplicates bool `yaml:"group_duplicates" json:"group_duplicates"`
 }
 
+// Bulk execution strategies for GeneralConfig.BulkExecution.
+const (
+	BulkExecutionSequential = "sequential"
+	BulkExecutionConcurrent = "concurrent"
+)
+
+// Modes for GeneralConfig.NoAnnotationMode, applied when no task has
+// anything actionable.
+const (
+	// NoAnnotationSingle runs the hook only when exactly one task matched
+	NoAnnotationSingle = "single"
+	// NoAnnotationEach runs the hook once for every matched task
+	NoAnnotationEach = "each"
+	// NoAnnotationPrompt lists the tasks and asks for annotations to add
+	NoAnnotationPrompt = "prompt"
+)
+
 // CLIConfig contains CLI-specific configuration.
 type CLIConfig struct {
 	// Default subcommand when none specified
@@ -80,9 +115,12 @@ func DefaultConfig() *Config {
 			PathExt:          "",
 			TaskAttributes:   "priority,project,tags,description",
 			NoAnnotationHook: "task $ID annotate",
+			NoAnnotationMode: NoAnnotationSingle,
 			Sort:             "urgency-,annot",
 			BaseFilter:       "+PENDING",
 			Debug:            false,
+			BulkExecution:    BulkExecutionSequential,
+			FilterWorkers:    4,
 		},
 		Actions: []types.Action{
 			{
@@ -124,6 +162,15 @@ func DefaultConfig() *Config {
 	}
 }
 
+// isValidSubcommand checks if name is one of the mode subcommands.
+func isValidSubcommand(name string) bool {
+	switch name {
+	case "normal", "any", "batch":
+		return true
+	}
+	return false
+}
+
 // getOpenCommand returns the appropriate open command for the platform.
 func getOpenCommand() string {
 	// Check environment variable first
@@ -222,6 +269,42 @@ func (c *Config) Validate() error {
 		})
 	}
 
+	switch c.General.BulkExecution {
+	case "", BulkExecutionSequential, BulkExecutionConcurrent:
+	default:
+		validationErrors = append(validationErrors, types.ValidationError{
+			Field:   "general.bulk_execution",
+			Value:   c.General.BulkExecution,
+			Message: "bulk execution must be one of: sequential, concurrent",
+		})
+	}
+
+	switch c.General.NoAnnotationMode {
+	case "", NoAnnotationSingle, NoAnnotationEach, NoAnnotationPrompt:
+	default:
+		validationErrors = append(validationErrors, types.ValidationError{
+			Field:   "general.no_annotation_mode",
+			Value:   c.General.NoAnnotationMode,
+			Message: "no annotation mode must be one of: single, each, prompt",
+		})
+	}
+
+	if _, err := types.ParseSortKeys(c.General.Sort); err != nil {
+		validationErrors = append(validationErrors, types.ValidationError{
+			Field:   "general.sort",
+			Value:   c.General.Sort,
+			Message: err.Error(),
+		})
+	}
+
+	if c.General.FilterWorkers < 0 {
+		validationErrors = append(validationErrors, types.ValidationError{
+			Field:   "general.filter_workers",
+			Value:   fmt.Sprintf("%d", c.General.FilterWorkers),
+			Message: "filter workers cannot be negative",
+		})
+	}
+
 	// Validate actions
 	if len(c.Actions) == 0 {
 		validationErrors = append(validationErrors, types.ValidationError{
@@ -257,6 +340,18 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for i, action := range c.Actions {
+		for _, fallback := range action.Fallback {
+			if fallback == action.Name || !actionNames[fallback] {
+				validationErrors = append(validationErrors, types.ValidationError{
+					Field:   fmt.Sprintf("actions[%d].fallback", i),
+					Value:   fallback,
+					Message: "fallback must name another configured action",
+				})
+			}
+		}
+	}
+
 	// Validate CLI configuration
 	if c.CLI.DefaultSubcommand == "" {
 		validationErrors = append(validationErrors, types.ValidationError{
@@ -264,6 +359,25 @@ func (c *Config) Validate() error {
 			Value:   c.CLI.DefaultSubcommand,
 			Message: "default subcommand is required",
 		})
+	} else if !isValidSubcommand(c.CLI.DefaultSubcommand) {
+		validationErrors = append(validationErrors, types.ValidationError{
+			Field:   "cli.default_subcommand",
+			Value:   c.CLI.DefaultSubcommand,
+			Message: "default subcommand must be one of: normal, any, batch",
+		})
+	}
+
+	for group := range c.CLI.Groups {
+		members, _ := c.GroupMembers(group)
+		for _, member := range members {
+			if !actionNames[member] {
+				validationErrors = append(validationErrors, types.ValidationError{
+					Field:   fmt.Sprintf("cli.groups.%s", group),
+					Value:   member,
+					Message: "group refers to an unknown action",
+				})
+			}
+		}
 	}
 
 	if len(validationErrors) > 0 {
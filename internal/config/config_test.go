@@ -91,6 +91,148 @@ func TestConfigValidation(t *testing.T) {
 			wantError: true,
 			errorText: "duplicate action name",
 		},
+		{
+			name: "unknown default subcommand",
+			config: &Config{
+				General: GeneralConfig{
+					Editor:  "vim",
+					TaskBin: "task",
+				},
+				Actions: []types.Action{{
+					Name:    "test",
+					Target:  "annotations",
+					Command: "echo test",
+				}},
+				CLI: CLIConfig{DefaultSubcommand: "interactive"},
+			},
+			wantError: true,
+			errorText: "default subcommand must be one of",
+		},
+		{
+			name: "unknown bulk execution strategy",
+			config: &Config{
+				General: GeneralConfig{
+					Editor:        "vim",
+					TaskBin:       "task",
+					BulkExecution: "parallel",
+				},
+				Actions: []types.Action{{
+					Name:    "test",
+					Target:  "annotations",
+					Command: "echo test",
+				}},
+				CLI: CLIConfig{DefaultSubcommand: "normal"},
+			},
+			wantError: true,
+			errorText: "bulk execution must be one of",
+		},
+		{
+			name: "sort by UDA named like an actionable key",
+			config: &Config{
+				General: GeneralConfig{
+					Editor:  "vim",
+					TaskBin: "task",
+					Sort:    "actionable-,annot",
+				},
+				Actions: []types.Action{{
+					Name:    "test",
+					Target:  "annotations",
+					Command: "echo test",
+				}},
+				CLI: CLIConfig{DefaultSubcommand: "normal"},
+			},
+			wantError: false,
+		},
+		{
+			name: "unknown sort key",
+			config: &Config{
+				General: GeneralConfig{
+					Editor:  "vim",
+					TaskBin: "task",
+					Sort:    "urgency-,annotation",
+				},
+				Actions: []types.Action{{
+					Name:    "test",
+					Target:  "annotations",
+					Command: "echo test",
+				}},
+				CLI: CLIConfig{DefaultSubcommand: "normal"},
+			},
+			wantError: true,
+			errorText: `unknown sort key "annotation"`,
+		},
+		{
+			name: "unknown no annotation mode",
+			config: &Config{
+				General: GeneralConfig{
+					Editor:           "vim",
+					TaskBin:          "task",
+					NoAnnotationMode: "always",
+				},
+				Actions: []types.Action{{
+					Name:    "test",
+					Target:  "annotations",
+					Command: "echo test",
+				}},
+				CLI: CLIConfig{DefaultSubcommand: "normal"},
+			},
+			wantError: true,
+			errorText: "no annotation mode must be one of",
+		},
+		{
+			name: "negative filter workers",
+			config: &Config{
+				General: GeneralConfig{
+					Editor:        "vim",
+					TaskBin:       "task",
+					FilterWorkers: -1,
+				},
+				Actions: []types.Action{{
+					Name:    "test",
+					Target:  "annotations",
+					Command: "echo test",
+				}},
+				CLI: CLIConfig{DefaultSubcommand: "normal"},
+			},
+			wantError: true,
+			errorText: "filter workers cannot be negative",
+		},
+		{
+			name: "unknown fallback action",
+			config: &Config{
+				General: GeneralConfig{
+					Editor:  "vim",
+					TaskBin: "task",
+				},
+				Actions: []types.Action{{
+					Name:     "test",
+					Target:   "annotations",
+					Command:  "echo test",
+					Fallback: []string{"missing"},
+				}},
+				CLI: CLIConfig{DefaultSubcommand: "normal"},
+			},
+			wantError: true,
+			errorText: "fallback must name another configured action",
+		},
+		{
+			name: "invalid when condition",
+			config: &Config{
+				General: GeneralConfig{
+					Editor:  "vim",
+					TaskBin: "task",
+				},
+				Actions: []types.Action{{
+					Name:    "test",
+					Target:  "annotations",
+					Command: "echo test",
+					When:    "due.before:now+2x",
+				}},
+				CLI: CLIConfig{DefaultSubcommand: "normal"},
+			},
+			wantError: true,
+			errorText: "invalid condition",
+		},
 	}
 
 	for _, tt := range tests {
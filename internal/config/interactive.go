@@ -247,9 +247,15 @@ general:
   taskargs: []
   task_attributes: "priority,project,tags,description"
   no_annotation_hook: "task $ID annotate"
+  no_annotation_mode: "single"  # or "each", "prompt"
   sort: "urgency-,annot"
   base_filter: "+PENDING"
   debug: false
+  bulk_execution: "sequential"  # or "concurrent"
+  filter_workers: 4
+  filter_cache: false
+  fallback_to_next_match: false
+  group_duplicates: false
 
 actions:
   - name: "files"
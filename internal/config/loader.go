@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -45,6 +46,7 @@ func Load(configPath string) (*Config, error) {
 	// Validate the configuration
 	if err := config.Validate(); err != nil {
 		return nil, errors.Wrap(err, errors.ConfigInvalid, "Configuration validation failed").
+			WithDetails(err.Error()).
 			WithSuggestions([]string{
 				"Check required fields",
 				"Verify action definitions",
@@ -90,5 +92,11 @@ func ValidateFile(configPath string) error {
 	fmt.Printf("  - Editor: %s\n", config.General.Editor)
 	fmt.Printf("  - Task binary: %s\n", config.General.TaskBin)
 
+	for _, action := range config.Actions {
+		if vars := action.CaptureVariables(); len(vars) > 0 {
+			fmt.Printf("  - %s exports: %s\n", action.Name, strings.Join(vars, ", "))
+		}
+	}
+
 	return nil
 }
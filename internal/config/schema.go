@@ -76,6 +76,13 @@ func GenerateJSONSchema() ([]byte, error) {
 						"default":     "task $ID annotate",
 					},
 
+					"no_annotation_mode": map[string]any{
+						"type":        "string",
+						"description": "Run the no-annotation hook for a single task, for each task, or prompt for annotations",
+						"default":     "single",
+						"enum":        []string{"single", "each", "prompt"},
+					},
+
 					"sort": map[string]any{
 						"type":        "string",
 						"description": "Default task sort order",
@@ -95,6 +102,38 @@ func GenerateJSONSchema() ([]byte, error) {
 						"description": "Enable debug output",
 						"default":     false,
 					},
+
+					"bulk_execution": map[string]any{
+						"type":        "string",
+						"description": "How several selected actions are executed",
+						"default":     "sequential",
+						"enum":        []string{"sequential", "concurrent"},
+					},
+
+					"filter_workers": map[string]any{
+						"type":        "integer",
+						"description": "Maximum number of filter commands run at the same time",
+						"default":     4,
+						"minimum":     0,
+					},
+
+					"filter_cache": map[string]any{
+						"type":        "boolean",
+						"description": "Run each distinct expanded filter command only once per run",
+						"default":     false,
+					},
+
+					"fallback_to_next_match": map[string]any{
+						"type":        "boolean",
+						"description": "Try the next action matching the same text when a command fails",
+						"default":     false,
+					},
+
+					"group_duplicates": map[string]any{
+						"type":        "boolean",
+						"description": "Merge actionables of several tasks whose expanded commands are identical",
+						"default":     false,
+					},
 				},
 			},
 
@@ -165,6 +204,27 @@ func GenerateJSONSchema() ([]byte, error) {
 							"description": "Command to execute inline with task display",
 							"default":     "",
 						},
+
+						"priority": map[string]any{
+							"type":        "integer",
+							"description": "Preference among matching actions; higher wins in single mode and for the action.priority sort key",
+							"default":     0,
+						},
+
+						"fallback": map[string]any{
+							"type":        "array",
+							"description": "Actions to try in order when this action's command fails",
+							"items": map[string]any{
+								"type": "string",
+							},
+							"uniqueItems": true,
+						},
+
+						"when": map[string]any{
+							"type":        "string",
+							"description": "Condition on task attributes that must hold for the action to be offered",
+							"examples":    []string{"+work and project.startswith:client", "due.before:now+2d or priority:H"},
+						},
 					},
 				},
 			},
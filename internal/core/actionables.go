@@ -3,13 +3,16 @@ package core
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/johnconnor-sec/taskopen-go/internal/errors"
 	"github.com/johnconnor-sec/taskopen-go/internal/exec"
 	"github.com/johnconnor-sec/taskopen-go/internal/output"
 	"github.com/johnconnor-sec/taskopen-go/internal/security"
+	"github.com/johnconnor-sec/taskopen-go/internal/shellwords"
 	"github.com/johnconnor-sec/taskopen-go/internal/types"
 	"github.com/johnconnor-sec/taskopen-go/internal/ui"
 )
@@ -22,70 +25,142 @@ type Actionable struct {
 	Entry       string            `json:"entry"`
 	Action      types.Action      `json:"action"`
 	Environment map[string]string `json:"environment"`
+	Inline      string            `json:"inline,omitempty"`
+
+	// alternatives are the other actions that matched the same text, in
+	// the order they are tried by fallback_to_next_match
+	alternatives []*Actionable
+
+	// related holds the actionables merged into this one by
+	// group_duplicates, including itself
+	related []*Actionable
+
+	// overridden is set when --execute replaced the configured command
+	overridden bool
 }
 
-// findActionableItems finds all actionable items across tasks
-func (tp *TaskProcessor) findActionableItems(ctx context.Context, tasks []map[string]any, single bool) ([]*Actionable, error) {
-	var actionables []*Actionable
+// selectActions returns the configured actions that are available in the
+// requested mode and pass the include/exclude lists
+func (tp *TaskProcessor) selectActions(opts ProcessOptions) []compiledAction {
+	var actions []compiledAction
+
+	for _, action := range tp.actions {
+		if !action.SupportsMode(string(opts.Mode)) {
+			continue
+		}
+		if len(opts.Include) > 0 && !slices.Contains(opts.Include, action.Name) {
+			continue
+		}
+		if slices.Contains(opts.Exclude, action.Name) {
+			continue
+		}
+		actions = append(actions, action)
+	}
+
+	return actions
+}
 
-	// Build action map by target
-	actionMap := make(map[string][]types.Action)
-	for _, action := range tp.config.Actions {
+// findActionableItems finds all actionable items across tasks using the given
+// candidate actions. Regexes are matched first; the filter commands of all
+// matches are then evaluated together by applyFilters.
+func (tp *TaskProcessor) findActionableItems(ctx context.Context, tasks []map[string]any, candidates []compiledAction, single bool) ([]*Actionable, error) {
+	var groups [][]*Actionable
+
+	// Build action map by target, remembering the order in which targets
+	// first appear so that matching does not depend on map iteration
+	actionMap := make(map[string][]compiledAction)
+	var targets []string
+	for _, action := range candidates {
 		if actionMap[action.Target] == nil {
-			actionMap[action.Target] = make([]types.Action, 0)
+			actionMap[action.Target] = make([]compiledAction, 0)
+			targets = append(targets, action.Target)
 		}
 		actionMap[action.Target] = append(actionMap[action.Target], action)
 	}
 
+	udaTypes := tp.udaTypes()
+
 	// Process each task
+	now := time.Now()
 	for _, task := range tasks {
 		baseEnv := tp.buildEnvironment(task)
 
-		// Process each task attribute
-		for attr, value := range task {
-			actions, hasActions := actionMap[attr]
-			if !hasActions {
+		entry := ""
+		if entryVal, hasEntry := task["entry"]; hasEntry {
+			entry = fmt.Sprintf("%v", entryVal)
+		}
+
+		for _, target := range targets {
+			actions := actionsForTask(actionMap[target], task, now)
+			if len(actions) == 0 {
+				continue
+			}
+			value, ok := targetValue(task, target)
+			if !ok {
+				continue
+			}
+
+			if target == "annotations" {
+				groups = append(groups, tp.matchAnnotations(task, baseEnv, value, actions, single)...)
 				continue
 			}
 
-			if attr == "annotations" {
-				// Handle annotations array
-				if annotations, ok := value.([]any); ok {
-					for _, annInterface := range annotations {
-						if ann, ok := annInterface.(map[string]any); ok {
-							if desc, hasDesc := ann["description"].(string); hasDesc {
-								entry := ""
-								if entryVal, hasEntry := ann["entry"]; hasEntry {
-									entry = fmt.Sprintf("%v", entryVal)
-								}
-
-								matches := tp.matchActionsLabel(ctx, baseEnv, desc, actions, single)
-								for _, match := range matches {
-									match.Entry = entry
-									actionables = append(actionables, match)
-								}
-							}
-						}
-					}
-				}
-			} else {
-				// Handle regular attributes
-				text := fmt.Sprintf("%v", value)
-				entry := ""
-				if entryVal, hasEntry := task["entry"]; hasEntry {
-					entry = fmt.Sprintf("%v", entryVal)
+			// Handle regular attributes, one match per array element
+			for _, text := range targetElements(target, value, udaTypes[target]) {
+				env := baseEnv
+				if target == dependsTarget {
+					env = tp.copyEnvironment(baseEnv)
+					env["DEPENDS_UUID"] = text
 				}
 
-				matches := tp.matchActionsPure(ctx, baseEnv, text, actions, single)
+				matches := tp.matchActionsPure(env, text, actions, single)
 				for _, match := range matches {
 					match.Entry = entry
-					actionables = append(actionables, match)
+					match.Task = task
 				}
+				groups = append(groups, matches)
+			}
+		}
+	}
+
+	return tp.applyFilters(ctx, groups, single)
+}
+
+// matchAnnotations matches actions against every annotation of a task and
+// returns the matches of each link in an annotation as a separate group
+func (tp *TaskProcessor) matchAnnotations(task map[string]any, baseEnv map[string]string, value any, actions []compiledAction, single bool) [][]*Actionable {
+	var groups [][]*Actionable
+
+	annotations, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	for _, annInterface := range annotations {
+		ann, ok := annInterface.(map[string]any)
+		if !ok {
+			continue
+		}
+		desc, hasDesc := ann["description"].(string)
+		if !hasDesc {
+			continue
+		}
+
+		entry := ""
+		if entryVal, hasEntry := ann["entry"]; hasEntry {
+			entry = fmt.Sprintf("%v", entryVal)
+		}
+
+		for _, matches := range tp.matchAnnotationLinks(baseEnv, desc, actions, single) {
+			for _, match := range matches {
+				match.Entry = entry
+				match.Task = task
 			}
+			groups = append(groups, matches)
 		}
 	}
 
-	return actionables, nil
+	return groups
 }
 
 // listActionables lists all actionable items for user selection
@@ -95,6 +170,14 @@ func (tp *TaskProcessor) listActionables(actionables []*Actionable) error {
 	for i, actionable := range actionables {
 		tp.formatter.List("%d. %s: %s", i+1, actionable.Action.Name, actionable.Text)
 		tp.formatter.Info("   Command: %s", actionable.Action.Command)
+		if descriptions := actionable.taskDescriptions(); len(descriptions) > 1 {
+			tp.formatter.Info("   Tasks: %s", strings.Join(descriptions, ", "))
+		}
+		if actionable.Inline != "" {
+			for line := range strings.SplitSeq(actionable.Inline, "\n") {
+				tp.formatter.Info("   │ %s", line)
+			}
+		}
 	}
 
 	fmt.Println()
@@ -103,39 +186,29 @@ func (tp *TaskProcessor) listActionables(actionables []*Actionable) error {
 	return nil
 }
 
-// sortActionables sorts actionable items according to configuration
-func (tp *TaskProcessor) sortActionables(actionables []*Actionable) {
-	sortKeys := tp.parseSortKeys(tp.config.General.Sort)
-
-	sort.Slice(actionables, func(i, j int) bool {
+// sortActionables sorts actionable items by the given keys. Missing values
+// sort last in either direction. The sort is stable: actionables that tie on
+// every key keep the order in which they were found, i.e. task order from
+// Taskwarrior, then actions by priority and configuration order, then
+// annotation order.
+func (tp *TaskProcessor) sortActionables(actionables []*Actionable, sortKeys []types.SortKey) {
+	sort.SliceStable(actionables, func(i, j int) bool {
 		a, b := actionables[i], actionables[j]
 
 		for _, sortKey := range sortKeys {
-			var result int
-
-			switch sortKey.Key {
-			case "annot":
-				result = strings.Compare(a.Text, b.Text)
-			case "entry":
-				result = strings.Compare(a.Entry, b.Entry)
-			case "id":
-				aID := tp.getTaskInt(a.Task, "id")
-				bID := tp.getTaskInt(b.Task, "id")
-				result = aID - bID
-			case "urgency":
-				aUrgency := tp.getTaskFloat(a.Task, "urgency")
-				bUrgency := tp.getTaskFloat(b.Task, "urgency")
-				if aUrgency < bUrgency {
-					result = -1
-				} else if aUrgency > bUrgency {
-					result = 1
-				}
-			default:
-				aVal := tp.getTaskString(a.Task, sortKey.Key)
-				bVal := tp.getTaskString(b.Task, sortKey.Key)
-				result = strings.Compare(aVal, bVal)
+			aVal, bVal := sortValue(a, sortKey.Key), sortValue(b, sortKey.Key)
+
+			aMissing, bMissing := isMissingSortValue(aVal), isMissingSortValue(bVal)
+			switch {
+			case aMissing && bMissing:
+				continue
+			case aMissing:
+				return false
+			case bMissing:
+				return true
 			}
 
+			result := compareSortValues(aVal, bVal)
 			if result != 0 {
 				if sortKey.Desc {
 					return result > 0
@@ -147,18 +220,23 @@ func (tp *TaskProcessor) sortActionables(actionables []*Actionable) {
 	})
 }
 
-// executeActionable executes a single actionable item
-func (tp *TaskProcessor) executeActionable(ctx context.Context, actionable *Actionable) error {
+// runActionable executes a single actionable item without fallbacks
+func (tp *TaskProcessor) runActionable(ctx context.Context, actionable *Actionable) error {
 	tp.logger.Info("Executing action", map[string]any{
 		"action": actionable.Action.Name,
 		"text":   actionable.Text,
 	})
 
-	// Expand environment variables in command
-	command := tp.expandEnvironmentVars(actionable.Action.Command, actionable.Environment)
+	// Expand environment variables in command, quoting the substituted values
+	command := expandVariables(actionable.Action.Command, actionable.Environment, true)
 
 	tp.formatter.Info("Executing: %s", command)
 
+	// Configured commands are trusted; ad-hoc --execute commands are not
+	if risk := tp.assessCommandRisk(command); actionable.overridden && risk != "SAFE" {
+		tp.formatter.Warning("Risk level: %s", risk)
+	}
+
 	// Check if this is a built-in command
 	if tp.builtinHandler.IsBuiltinCommand(command) {
 		return tp.builtinHandler.ExecuteBuiltinCommand(ctx, command, actionable.Environment)
@@ -181,7 +259,12 @@ func (tp *TaskProcessor) executeActionable(ctx context.Context, actionable *Acti
 			"command": command,
 		})
 		// Use direct execution for simple commands (better for interactive programs)
-		result, err = tp.executor.ExecuteDirect(ctx, command,
+		words, splitErr := shellwords.Split(command)
+		if splitErr != nil || len(words) == 0 {
+			return errors.New(errors.ActionExecution, "Failed to parse command").
+				WithDetails(fmt.Sprintf("Command: %s", command))
+		}
+		result, err = tp.executor.ExecuteDirectArgs(ctx, words[0], words[1:],
 			&exec.ExecutionOptions{Environment: actionable.Environment})
 	}
 
@@ -194,7 +277,7 @@ func (tp *TaskProcessor) executeActionable(ctx context.Context, actionable *Acti
 		if result.Stderr != "" {
 			tp.formatter.Error("Error output: %s", result.Stderr)
 		}
-		return errors.New(errors.ActionExecution, "Command execution failed")
+		return errors.New(errors.ActionExecution, fmt.Sprintf("Command exited with code %d", result.ExitCode))
 	}
 
 	if result.Stdout != "" {
@@ -236,27 +319,31 @@ func (tp *TaskProcessor) interactiveSelection(ctx context.Context, actionables [
 	}
 
 	// Handle cancellation
-	if selected == nil {
+	if len(selected) == 0 {
 		tp.formatter.Info("Action cancelled by user")
 		return nil
 	}
 
-	// Find the corresponding actionable
-	selectedIndex := -1
-	for i, item := range menuItems {
-		if item.ID == selected.ID {
-			selectedIndex = i
-			break
+	// Find the corresponding actionables
+	chosen := make([]*Actionable, 0, len(selected))
+	for _, item := range selected {
+		data, ok := item.Data.(map[string]any)
+		if !ok {
+			return fmt.Errorf("selected item not found")
+		}
+		index, ok := data["index"].(int)
+		if !ok || index < 0 || index >= len(actionables) {
+			return fmt.Errorf("selected item not found")
 		}
+		chosen = append(chosen, actionables[index])
 	}
 
-	if selectedIndex == -1 {
-		return fmt.Errorf("selected item not found")
+	if len(chosen) == 1 {
+		tp.formatter.Success("Executing: %s", chosen[0].Text)
+		return tp.executeActionable(ctx, chosen[0])
 	}
 
-	// Execute the selected actionable
-	tp.formatter.Success("Executing: %s", selected.Text)
-	return tp.executeActionable(ctx, actionables[selectedIndex])
+	return tp.executeBulk(ctx, chosen)
 }
 
 // convertActionablesToMenuItems converts actionable items to interactive menu items
@@ -270,10 +357,17 @@ func (tp *TaskProcessor) convertActionablesToMenuItems(actionables []*Actionable
 			actionable.Action.Command)
 
 		// Add task context if available
-		if actionable.Task != nil {
-			if desc, ok := actionable.Task["description"].(string); ok && desc != "" {
-				description = fmt.Sprintf("Task: %s | %s", desc, description)
-			}
+		switch descriptions := actionable.taskDescriptions(); len(descriptions) {
+		case 0:
+		case 1:
+			description = fmt.Sprintf("Task: %s | %s", descriptions[0], description)
+		default:
+			description = fmt.Sprintf("Tasks (%d): %s | %s", len(descriptions), strings.Join(descriptions, ", "), description)
+		}
+
+		// Add inline command output if available
+		if actionable.Inline != "" {
+			description = fmt.Sprintf("%s | %s", firstLine(actionable.Inline), description)
 		}
 
 		items[i] = ui.MenuItem{
@@ -284,6 +378,7 @@ func (tp *TaskProcessor) convertActionablesToMenuItems(actionables []*Actionable
 				"actionable": actionable,
 				"command":    actionable.Action.Command,
 				"action":     actionable.Action.Name,
+				"inline":     actionable.Inline,
 				"index":      i,
 			},
 			Action: func() error {
@@ -306,6 +401,7 @@ func (tp *TaskProcessor) createMenuConfig() ui.MenuConfig {
 	config.AllowSearch = true
 	config.VimMode = true // Enable vim navigation by default
 	config.MaxItems = 15  // Show more items for better overview
+	config.AllowMultiSelect = true
 
 	// Add preview function for commands
 	config.PreviewFunc = tp.createActionablePreview()
@@ -338,7 +434,7 @@ func (tp *TaskProcessor) createActionablePreview() func(ui.MenuItem) string {
 		// Command preview (sanitized to hide sensitive info)
 		command := actionable.Action.Command
 		// Expand environment variables for display but sanitize them
-		expandedCommand := tp.expandEnvironmentVars(command, actionable.Environment)
+		expandedCommand := expandVariables(command, actionable.Environment, true)
 		preview.WriteString(fmt.Sprintf("📋 Command: %s\n", expandedCommand))
 
 		// Risk assessment
@@ -346,7 +442,12 @@ func (tp *TaskProcessor) createActionablePreview() func(ui.MenuItem) string {
 		preview.WriteString(fmt.Sprintf("⚠️  Risk Level: %s\n", risk))
 
 		// Task information
-		if actionable.Task != nil {
+		if descriptions := actionable.taskDescriptions(); len(descriptions) > 1 {
+			preview.WriteString(fmt.Sprintf("📝 Tasks (%d):\n", len(descriptions)))
+			for _, desc := range descriptions {
+				preview.WriteString(fmt.Sprintf("   • %s\n", desc))
+			}
+		} else if actionable.Task != nil {
 			if desc, ok := actionable.Task["description"].(string); ok && desc != "" {
 				preview.WriteString(fmt.Sprintf("📝 Task: %s\n", desc))
 			}
@@ -358,12 +459,21 @@ func (tp *TaskProcessor) createActionablePreview() func(ui.MenuItem) string {
 			}
 		}
 
+		// Inline command output
+		if actionable.Inline != "" {
+			preview.WriteString("\n💬 Inline:\n")
+			for line := range strings.SplitSeq(actionable.Inline, "\n") {
+				preview.WriteString(fmt.Sprintf("   %s\n", line))
+			}
+		}
+
 		// Environment variables (securely sanitized)
 		if len(actionable.Environment) > 0 {
 			preview.WriteString("\n🔧 Task Variables (Sanitized):\n")
 
 			// Show only important task-related vars, sanitized
-			importantVars := []string{"UUID", "ID", "FILE", "ANNOTATION", "LABEL", "LAST_MATCH"}
+			importantVars := []string{"UUID", "ID", "UUIDS", "IDS", "FILE", "ANNOTATION", "LABEL", "LINK_TEXT", "LAST_MATCH"}
+			importantVars = append(importantVars, actionable.Action.CaptureVariables()...)
 			for _, varName := range importantVars {
 				if value, exists := actionable.Environment[varName]; exists {
 					sanitizedValue := sanitizer.SanitizeValue(varName, value)
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+// writeFakeTaskBin writes a task stand-in that exports tasksJSON and
+// returns its path. TASKRC points at an empty file, so no context applies.
+func writeFakeTaskBin(t *testing.T, tasksJSON string) string {
+	t.Helper()
+	useEmptyTaskrc(t)
+	path := filepath.Join(t.TempDir(), "task")
+	script := "#!/bin/sh\ncase \"$*\" in\n*_get*) exit 0 ;;\nesac\ncat <<'JSON'\n" + tasksJSON + "\nJSON\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useEmptyTaskrc points TASKRC at an empty file for the rest of the test
+func useEmptyTaskrc(t *testing.T) {
+	t.Helper()
+	taskrc := filepath.Join(t.TempDir(), "taskrc")
+	if err := os.WriteFile(taskrc, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TASKRC", taskrc)
+}
+
+func TestSelectActions(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Actions = []types.Action{
+		{Name: "open", Target: "annotations", Regex: `.*`, Command: "true"},
+		{Name: "edit", Target: "annotations", Regex: `.*`, Command: "true", Modes: []string{"normal", "any"}},
+		{Name: "sync", Target: "annotations", Regex: `.*`, Command: "true", Modes: []string{"batch"}},
+		{Name: "view", Target: "annotations", Regex: `.*`, Command: "true", Modes: []string{"any"}},
+	}
+	cfg.CLI.Groups = map[string]string{"editing": "edit, view"}
+	tp := NewTaskProcessor(cfg)
+
+	resolve := func(names ...string) []string {
+		resolved, err := cfg.ResolveActionNames(names)
+		if err != nil {
+			t.Fatalf("ResolveActionNames(%q) error = %v", names, err)
+		}
+		return resolved
+	}
+
+	tests := []struct {
+		name string
+		opts ProcessOptions
+		want []string
+	}{
+		{"normal", ProcessOptions{Mode: ModeNormal}, []string{"open", "edit"}},
+		{"any", ProcessOptions{Mode: ModeAny}, []string{"open", "edit", "view"}},
+		{"batch", ProcessOptions{Mode: ModeBatch}, []string{"open", "sync"}},
+		{"include group", ProcessOptions{Mode: ModeAny, Include: resolve("editing")}, []string{"edit", "view"}},
+		{"exclude group", ProcessOptions{Mode: ModeAny, Exclude: resolve("editing")}, []string{"open"}},
+		{"include outside mode", ProcessOptions{Mode: ModeBatch, Include: resolve("editing", "sync")}, []string{"sync"}},
+		{"include and exclude", ProcessOptions{Mode: ModeAny, Include: resolve("editing", "open"), Exclude: resolve("view")}, []string{"open", "edit"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, action := range tp.selectActions(tt.opts) {
+				got = append(got, action.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectActions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectActionables_Modes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.General.TaskBin = writeFakeTaskBin(t, `[{"id":1,"uuid":"a","description":"Review",
+  "annotations":[{"entry":"20240102T100000Z","description":"~/spec.pdf"}]}]`)
+	cfg.Actions = []types.Action{
+		{Name: "pdf", Target: "annotations", Regex: `\.pdf$`, Command: "true"},
+		{Name: "any", Target: "annotations", Regex: `.*`, Command: "true"},
+		{Name: "print", Target: "annotations", Regex: `\.pdf$`, Command: "true", Modes: []string{"batch"}},
+	}
+	tp := NewTaskProcessor(cfg)
+
+	tests := []struct {
+		name string
+		opts ProcessOptions
+		want []string
+	}{
+		{"normal single", ProcessOptions{Mode: ModeNormal, Single: true}, []string{"pdf"}},
+		{"normal all", ProcessOptions{Mode: ModeNormal}, []string{"pdf", "any"}},
+		{"any ignores single", ProcessOptions{Mode: ModeAny, Single: true}, []string{"pdf", "any"}},
+		{"batch actions", ProcessOptions{Mode: ModeBatch}, []string{"pdf", "any", "print"}},
+		{"default mode", ProcessOptions{Single: true}, []string{"pdf"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionables, _, err := tp.CollectActionables(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("CollectActionables() error = %v", err)
+			}
+			var got []string
+			for _, actionable := range actionables {
+				got = append(got, actionable.Action.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("actions = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessTasks_BatchRunsWithoutMenu(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "log")
+	cfg := config.DefaultConfig()
+	cfg.General.TaskBin = writeFakeTaskBin(t, `[{"id":1,"uuid":"a","description":"Review",
+  "annotations":[{"description":"/docs/a.pdf"},{"description":"/docs/b.pdf"}]}]`)
+	cfg.Actions = []types.Action{
+		{Name: "pdf", Target: "annotations", Regex: `\.pdf$`, Command: "echo $FILE >> " + log},
+	}
+	tp := NewTaskProcessor(cfg)
+
+	// Interactive is set to show that batch mode never opens the menu,
+	// which would fail without a terminal
+	if err := tp.ProcessTasks(context.Background(), ProcessOptions{Mode: ModeBatch, Interactive: true}); err != nil {
+		t.Fatalf("ProcessTasks() error = %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(string(data)); !reflect.DeepEqual(got, []string{"/docs/a.pdf", "/docs/b.pdf"}) {
+		t.Errorf("ran for %q, want both annotations", got)
+	}
+}
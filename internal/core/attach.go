@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+)
+
+// Attach annotates the task selected by filters with a labeled path or URL.
+// Paths must exist and are stored in ~-relative form.
+func (tp *TaskProcessor) Attach(ctx context.Context, filters []string, target, label string) error {
+	normalized, err := normalizeAttachment(target)
+	if err != nil {
+		return err
+	}
+
+	annotation, err := formatAnnotation(label, normalized)
+	if err != nil {
+		return err
+	}
+
+	if matches := tp.annotationMatches(annotation); len(matches) == 0 {
+		tp.formatter.Warning("Annotation '%s' does not match any configured action", annotation)
+	} else {
+		tp.logger.Debug("Annotation matches actions", map[string]any{"actions": matches})
+	}
+
+	tasks, err := tp.getTasksFromTaskwarrior(ctx, filters)
+	if err != nil {
+		return err
+	}
+
+	switch len(tasks) {
+	case 0:
+		return errors.New(errors.TaskwarriorQuery, "No tasks match the specified filter").
+			WithDetails(fmt.Sprintf("Filter: %s", strings.Join(filters, " ")))
+	case 1:
+	default:
+		return errors.New(errors.ValidationFailed, fmt.Sprintf("Filter matches %d tasks, expected exactly one", len(tasks))).
+			WithDetails(fmt.Sprintf("Filter: %s", strings.Join(filters, " "))).
+			WithSuggestion("Use a task ID or UUID to select a single task")
+	}
+
+	uuid := tp.getTaskString(tasks[0], "uuid")
+	if uuid == "" {
+		return errors.New(errors.TaskwarriorQuery, "Matched task has no UUID")
+	}
+
+	if err := tp.annotateTask(ctx, uuid, annotation); err != nil {
+		return err
+	}
+
+	tp.formatter.Success("Annotated task %s: %s", tp.getTaskString(tasks[0], "description"), annotation)
+	return nil
+}
+
+// annotateTask adds annotation to the task with the given UUID
+func (tp *TaskProcessor) annotateTask(ctx context.Context, uuid, annotation string) error {
+	if err := tp.client.Annotate(ctx, uuid, annotation); err != nil {
+		tp.logger.Error("Failed to annotate task", map[string]any{"uuid": uuid, "error": err.Error()})
+		return err
+	}
+	return nil
+}
+
+// annotationMatches returns the names of the annotation actions whose
+// regexes match annotation. Filter commands are not run because there is
+// no task environment yet.
+func (tp *TaskProcessor) annotationMatches(annotation string) []string {
+	var actions []compiledAction
+	for _, action := range tp.actions {
+		if action.Target == "annotations" {
+			actions = append(actions, action)
+		}
+	}
+
+	var names []string
+	for _, match := range tp.matchActionsLabel(map[string]string{}, annotation, actions, false) {
+		names = append(names, match.Action.Name)
+	}
+	return names
+}
+
+// formatAnnotation joins label and target the way matchActionsLabel splits them
+func formatAnnotation(label, target string) (string, error) {
+	if label == "" {
+		return target, nil
+	}
+	if strings.ContainsAny(label, " \t\n") || strings.HasSuffix(label, ":") {
+		return "", errors.New(errors.ValidationFailed, fmt.Sprintf("Invalid label: %q", label)).
+			WithSuggestion("Labels must be a single word without a trailing ':'")
+	}
+	return label + ": " + target, nil
+}
+
+// normalizeAttachment checks that a file target exists and rewrites it as an
+// absolute path, relative to ~ when it is inside the home directory. URLs are
+// returned unchanged.
+func normalizeAttachment(target string) (string, error) {
+	if isURL(target) {
+		return target, nil
+	}
+
+	home, _ := os.UserHomeDir()
+
+	path := target
+	if home != "" && (path == "~" || strings.HasPrefix(path, "~/")) {
+		path = filepath.Join(home, path[1:])
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", errors.Wrap(err, errors.ValidationFailed, "Invalid path").
+			WithDetails(fmt.Sprintf("Path: %s", target))
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.Wrap(err, errors.ValidationFailed, "File does not exist").
+			WithDetails(fmt.Sprintf("Path: %s", path)).
+			WithSuggestion("Check the path or attach a URL instead")
+	}
+
+	if home != "" {
+		if rel, err := filepath.Rel(home, path); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+			return filepath.Join("~", rel), nil
+		}
+	}
+	return path, nil
+}
+
+// isURL reports whether target looks like a URL rather than a file path
+func isURL(target string) bool {
+	return strings.Contains(target, "://") || strings.HasPrefix(target, "www.")
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+)
+
+func TestFormatAnnotation(t *testing.T) {
+	tests := []struct {
+		label   string
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{"", "~/file.pdf", "~/file.pdf", false},
+		{"doc", "~/file.pdf", "doc: ~/file.pdf", false},
+		{"two words", "~/file.pdf", "", true},
+		{"doc:", "~/file.pdf", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := formatAnnotation(tt.label, tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("formatAnnotation(%q, %q) error = %v, wantErr %v", tt.label, tt.target, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("formatAnnotation(%q, %q) = %q, want %q", tt.label, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAttachment(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	docs := filepath.Join(home, "docs")
+	if err := os.Mkdir(docs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(docs, "spec.pdf")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(docs)
+
+	tests := []struct {
+		name    string
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{"url unchanged", "https://example.com/a", "https://example.com/a", false},
+		{"absolute path in home", file, "~/docs/spec.pdf", false},
+		{"tilde path", "~/docs/spec.pdf", "~/docs/spec.pdf", false},
+		{"relative path", "spec.pdf", "~/docs/spec.pdf", false},
+		{"missing file", "missing.pdf", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeAttachment(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeAttachment(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeAttachment(%q) = %q, want %q", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnotationMatches(t *testing.T) {
+	tp := NewTaskProcessor(config.DefaultConfig())
+
+	if got := tp.annotationMatches("doc: ~/docs/spec.pdf"); !slices.Contains(got, "files") {
+		t.Errorf("annotationMatches() = %v, want files action", got)
+	}
+	if got := tp.annotationMatches("just some words"); len(got) != 0 {
+		t.Errorf("annotationMatches() = %v, want no matches", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/johnconnor-sec/taskopen-go/internal/errors"
 	"github.com/johnconnor-sec/taskopen-go/internal/exec"
 	"github.com/johnconnor-sec/taskopen-go/internal/output"
+	"github.com/johnconnor-sec/taskopen-go/internal/shellwords"
 )
 
 // BuiltinHandler handles built-in commands
@@ -42,9 +43,10 @@ func (bh *BuiltinHandler) IsBuiltinCommand(command string) bool {
 	return commandName == "editnote"
 }
 
-// ExecuteBuiltinCommand executes a built-in command
+// ExecuteBuiltinCommand executes a built-in command. Variables in command
+// must already be expanded.
 func (bh *BuiltinHandler) ExecuteBuiltinCommand(ctx context.Context, command string, env map[string]string) error {
-	args, err := bh.parseShellCommand(command)
+	args, err := shellwords.Split(command)
 	if err != nil {
 		return errors.Wrap(err, errors.ActionExecution, "Failed to parse command")
 	}
@@ -71,9 +73,9 @@ func (bh *BuiltinHandler) executeEditNote(ctx context.Context, args []string, en
 		return errors.New(errors.ActionExecution, "editnote requires exactly 3 arguments: <file-path> <description> <uuid>")
 	}
 
-	filePath := bh.expandEnvironmentVars(args[0], env)
-	description := bh.expandEnvironmentVars(args[1], env)
-	uuid := bh.expandEnvironmentVars(args[2], env)
+	filePath := args[0]
+	description := args[1]
+	uuid := args[2]
 
 	bh.logger.Debug("editnote", map[string]any{
 		"file_path":   filePath,
@@ -122,7 +124,7 @@ func (bh *BuiltinHandler) executeEditNote(ctx context.Context, args []string, en
 	bh.formatter.Info("Opening note with %s: %s", editor, filePath)
 
 	// Use intelligent execution logic with interactive editor support
-	command := fmt.Sprintf("%s %s", editor, filePath)
+	command := fmt.Sprintf("%s %s", editor, shellQuote(filePath))
 	var result *exec.ExecutionResult
 	var err error
 
@@ -146,7 +148,8 @@ func (bh *BuiltinHandler) executeEditNote(ctx context.Context, args []string, en
 			"interactive": isInteractive,
 		})
 		// Use direct execution for simple editor commands
-		result, err = bh.executor.ExecuteDirect(ctx, command, &exec.ExecutionOptions{
+		words := append(strings.Fields(editor), filePath)
+		result, err = bh.executor.ExecuteDirectArgs(ctx, words[0], words[1:], &exec.ExecutionOptions{
 			Environment: env,
 			Interactive: isInteractive, // No timeout for interactive editors
 		})
@@ -170,70 +173,3 @@ func (bh *BuiltinHandler) executeEditNote(ctx context.Context, args []string, en
 	bh.formatter.Success("Note editing completed")
 	return nil
 }
-
-// parseShellCommand parses a shell command string into arguments, handling quotes
-func (bh *BuiltinHandler) parseShellCommand(command string) ([]string, error) {
-	var args []string
-	var current strings.Builder
-	inQuotes := false
-	quoteChar := byte(0)
-	escaped := false
-
-	for i := 0; i < len(command); i++ {
-		char := command[i]
-
-		if escaped {
-			current.WriteByte(char)
-			escaped = false
-			continue
-		}
-
-		if char == '\\' {
-			escaped = true
-			continue
-		}
-
-		if !inQuotes {
-			if char == '"' || char == '\'' {
-				inQuotes = true
-				quoteChar = char
-				continue
-			}
-			if char == ' ' || char == '\t' {
-				if current.Len() > 0 {
-					args = append(args, current.String())
-					current.Reset()
-				}
-				continue
-			}
-		} else {
-			if char == quoteChar {
-				inQuotes = false
-				quoteChar = 0
-				continue
-			}
-		}
-
-		current.WriteByte(char)
-	}
-
-	if current.Len() > 0 {
-		args = append(args, current.String())
-	}
-
-	if inQuotes {
-		return nil, fmt.Errorf("unclosed quote in command: %s", command)
-	}
-
-	return args, nil
-}
-
-// expandEnvironmentVars expands environment variables in a string
-func (bh *BuiltinHandler) expandEnvironmentVars(text string, env map[string]string) string {
-	result := text
-	for key, value := range env {
-		result = strings.ReplaceAll(result, "$"+key, value)
-		result = strings.ReplaceAll(result, "${"+key+"}", value)
-	}
-	return result
-}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+	"github.com/johnconnor-sec/taskopen-go/internal/when"
+)
+
+// annotationSplitRegex splits an annotation into an optional "label:" and the file part
+var annotationSplitRegex = regexp.MustCompile(`^((\S+):\s+)?(.*)$`)
+
+// compiledAction is an action whose patterns are compiled once per run
+type compiledAction struct {
+	types.Action
+
+	regex      *regexp.Regexp
+	labelRegex *regexp.Regexp  // nil when the action has no label regex
+	when       *when.Condition // nil when the action has no when condition
+}
+
+// compileActions compiles the patterns of every action and orders them by
+// descending priority, keeping configuration order for equal priorities.
+// Actions with an invalid pattern are logged and left out.
+func (tp *TaskProcessor) compileActions(actions []types.Action) []compiledAction {
+	compiled := make([]compiledAction, 0, len(actions))
+
+	for _, action := range actions {
+		regex, err := regexp.Compile(action.Regex)
+		if err != nil {
+			tp.logger.Error("Invalid regex", map[string]any{"action": action.Name, "regex": action.Regex, "error": err.Error()})
+			continue
+		}
+
+		var labelRegex *regexp.Regexp
+		if action.LabelRegex != "" {
+			labelRegex, err = regexp.Compile(action.LabelRegex)
+			if err != nil {
+				tp.logger.Error("Invalid label regex", map[string]any{"action": action.Name, "regex": action.LabelRegex, "error": err.Error()})
+				continue
+			}
+		}
+
+		var condition *when.Condition
+		if action.When != "" {
+			condition, err = when.Parse(action.When)
+			if err != nil {
+				tp.logger.Error("Invalid when condition", map[string]any{"action": action.Name, "when": action.When, "error": err.Error()})
+				continue
+			}
+		}
+
+		compiled = append(compiled, compiledAction{Action: action, regex: regex, labelRegex: labelRegex, when: condition})
+	}
+
+	slices.SortStableFunc(compiled, func(a, b compiledAction) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+
+	return compiled
+}
+
+// actionsForTask returns the actions whose when condition holds for task
+func actionsForTask(actions []compiledAction, task map[string]any, now time.Time) []compiledAction {
+	if !slices.ContainsFunc(actions, func(action compiledAction) bool { return action.when != nil }) {
+		return actions
+	}
+	return slices.DeleteFunc(slices.Clone(actions), func(action compiledAction) bool {
+		return action.when != nil && !action.when.Matches(task, now)
+	})
+}
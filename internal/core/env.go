@@ -53,7 +53,7 @@ func (tp *TaskProcessor) buildEnvironment(task map[string]any) map[string]string
 
 // copyEnvironment creates a copy of environment map
 func (tp *TaskProcessor) copyEnvironment(baseEnv map[string]string) map[string]string {
-	env := make(map[string]string)
+	env := make(map[string]string, len(baseEnv)+4)
 	maps.Copy(env, baseEnv)
 	return env
 }
@@ -68,13 +68,3 @@ func (tp *TaskProcessor) expandPath(path string) string {
 	}
 	return path
 }
-
-// expandEnvironmentVars expands environment variables in a command string
-func (tp *TaskProcessor) expandEnvironmentVars(command string, env map[string]string) string {
-	result := command
-	for key, value := range env {
-		result = strings.ReplaceAll(result, "$"+key, value)
-		result = strings.ReplaceAll(result, "${"+key+"}", value)
-	}
-	return result
-}
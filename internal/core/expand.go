@@ -0,0 +1,162 @@
+package core
+
+import "strings"
+
+// expandVariables substitutes $VAR, ${VAR} and ${VAR:-default} references in
+// a single left-to-right pass, so substituted values are never expanded
+// again. A backslash escapes "$", single-quoted regions are copied unchanged
+// and unset variables expand to nothing.
+//
+// With shell set, text is a command line for sh: substituted values are
+// quoted so that each stays a single literal word. Only a program variable
+// such as $EDITOR at the start of the command is inserted unquoted, so that
+// it may carry its own arguments; task and annotation values never are.
+func expandVariables(text string, env map[string]string, shell bool) string {
+	var b strings.Builder
+	inDouble := false
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text):
+			if text[i+1] == '$' && !shell {
+				b.WriteByte('$')
+			} else {
+				b.WriteString(text[i : i+2])
+			}
+			i++
+
+		case c == '\'' && !inDouble:
+			end := strings.IndexByte(text[i+1:], '\'')
+			if end < 0 {
+				b.WriteString(text[i:])
+				return b.String()
+			}
+			b.WriteString(text[i : i+end+2])
+			i += end + 1
+
+		case c == '"':
+			inDouble = !inDouble
+			b.WriteByte(c)
+
+		case c == '$':
+			name, value, set, n := lookupVariable(text[i:], env)
+			if n == 0 {
+				b.WriteByte(c)
+				continue
+			}
+			if set {
+				switch {
+				case !shell:
+					b.WriteString(value)
+				case programVariables[name] && !inDouble && strings.TrimSpace(text[:i]) == "":
+					b.WriteString(value)
+				case inDouble:
+					b.WriteString(escapeDoubleQuoted(value))
+				default:
+					b.WriteString(shellQuote(value))
+				}
+			}
+			i += n - 1
+
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// programVariables are the environment variables naming a program the user
+// chose, which may be used unquoted as the command to run. Everything else,
+// including all task attributes and annotation matches, is always quoted.
+var programVariables = map[string]bool{
+	"EDITOR":   true,
+	"VISUAL":   true,
+	"BROWSER":  true,
+	"PAGER":    true,
+	"TERMINAL": true,
+	"SHELL":    true,
+}
+
+// lookupVariable resolves the variable reference at the start of ref and
+// returns its name, its value, whether it is set and the length of the
+// reference. A length of zero means ref does not start with a valid reference.
+func lookupVariable(ref string, env map[string]string) (string, string, bool, int) {
+	if len(ref) > 1 && ref[1] == '{' {
+		end := matchingBrace(ref)
+		if end < 0 {
+			return "", "", false, 0
+		}
+
+		name, fallback, hasFallback := strings.Cut(ref[2:end], ":-")
+		if !isVariableName(name) {
+			return "", "", false, 0
+		}
+
+		value, set := env[name]
+		if hasFallback && value == "" {
+			return name, expandVariables(fallback, env, false), true, end + 1
+		}
+		return name, value, set, end + 1
+	}
+
+	n := 1
+	for n < len(ref) && isVariableChar(ref[n], n == 1) {
+		n++
+	}
+	if n == 1 {
+		return "", "", false, 0
+	}
+
+	name := ref[1:n]
+	value, set := env[name]
+	return name, value, set, n
+}
+
+// matchingBrace returns the index of the brace closing the "${" at the start
+// of ref, or -1 when it is not closed
+func matchingBrace(ref string) int {
+	depth := 0
+	for i := 1; i < len(ref); i++ {
+		switch ref[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isVariableName reports whether name is a valid environment variable name
+func isVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isVariableChar(name[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isVariableChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (!first && c >= '0' && c <= '9')
+}
+
+// shellQuote quotes s as a single literal sh word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// escapeDoubleQuoted escapes the characters that keep their special meaning
+// inside a double-quoted sh string
+func escapeDoubleQuoted(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s)
+}
@@ -0,0 +1,59 @@
+package core
+
+import "testing"
+
+func TestExpandVariables(t *testing.T) {
+	env := map[string]string{
+		"ID":         "42",
+		"IDENTIFIER": "ident",
+		"MATCH_1":    "one",
+		"MATCH_10":   "ten",
+		"FILE":       "/tmp/a b.txt",
+		"EDITOR":     "code -w",
+		"PRICE":      "$HOME",
+		"EMPTY":      "",
+		"EVIL":       "x'; rm -rf ~; echo '",
+		"QUOTED":     `say "hi" $USER`,
+		"ANNOTATION": "x; touch pwned",
+	}
+
+	tests := []struct {
+		name  string
+		text  string
+		shell bool
+		want  string
+	}{
+		{"longest name wins", "$ID $IDENTIFIER", false, "42 ident"},
+		{"numbered captures", "$MATCH_1 $MATCH_10", false, "one ten"},
+		{"braces", "${ID}0", false, "420"},
+		{"default when unset", "${MISSING:-fallback}", false, "fallback"},
+		{"default when empty", "${EMPTY:-fallback}", false, "fallback"},
+		{"default not used", "${ID:-fallback}", false, "42"},
+		{"nested default", "${MISSING:-${ID}}", false, "42"},
+		{"unset is empty", "a${MISSING}b", false, "ab"},
+		{"values are not expanded again", "$PRICE", false, "$HOME"},
+		{"escaped dollar", `cost \$ID`, false, "cost $ID"},
+		{"single quotes", `'$ID' $ID`, false, "'$ID' 42"},
+		{"lone dollar", "a $ b $1", false, "a $ b $1"},
+		{"unclosed brace", "${ID", false, "${ID"},
+
+		{"shell quotes values", "xdg-open $FILE", true, "xdg-open '/tmp/a b.txt'"},
+		{"shell injection", "echo $EVIL", true, `echo 'x'\''; rm -rf ~; echo '\'''`},
+		{"shell double quotes", `echo "$QUOTED"`, true, `echo "say \"hi\" \$USER"`},
+		{"shell command word", "$EDITOR $FILE", true, "code -w '/tmp/a b.txt'"},
+		{"shell leading annotation", "$ANNOTATION", true, `'x; touch pwned'`},
+		{"shell leading file", "${FILE} --flag", true, "'/tmp/a b.txt' --flag"},
+		{"shell leading program default", "${EDITOR:-vi} $FILE", true, "code -w '/tmp/a b.txt'"},
+		{"shell keeps escape", `echo \$ID`, true, `echo \$ID`},
+		{"shell empty value", "echo $EMPTY", true, "echo ''"},
+		{"shell default", "echo ${MISSING:-a b}", true, "echo 'a b'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandVariables(tt.text, env, tt.shell); got != tt.want {
+				t.Errorf("expandVariables(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/security"
+)
+
+// ListFormat selects the machine-readable output format of `taskopen list`
+type ListFormat string
+
+const (
+	// ListFormatJSON writes a single JSON array
+	ListFormatJSON ListFormat = "json"
+	// ListFormatNDJSON writes one JSON object per line
+	ListFormatNDJSON ListFormat = "ndjson"
+	// ListFormatTSV writes tab-separated values with a header row
+	ListFormatTSV ListFormat = "tsv"
+)
+
+// ParseListFormat converts a format name into a ListFormat. An empty name
+// selects JSON.
+func ParseListFormat(name string) (ListFormat, error) {
+	switch ListFormat(strings.ToLower(name)) {
+	case "", ListFormatJSON:
+		return ListFormatJSON, nil
+	case ListFormatNDJSON:
+		return ListFormatNDJSON, nil
+	case ListFormatTSV:
+		return ListFormatTSV, nil
+	default:
+		return "", fmt.Errorf("unknown list format: %s", name)
+	}
+}
+
+// ActionableRecord is the exported form of an Actionable
+type ActionableRecord struct {
+	UUID        string            `json:"uuid"`
+	ID          int               `json:"id"`
+	Action      string            `json:"action"`
+	Annotation  string            `json:"annotation"`
+	Entry       string            `json:"entry"`
+	Command     string            `json:"command"`
+	Environment map[string]string `json:"environment,omitempty"`
+}
+
+// ActionableRecords converts actionables into records suitable for export.
+// Only variables set by taskopen are included, sensitive variables are
+// dropped and sensitive values are masked in the expanded command.
+func (tp *TaskProcessor) ActionableRecords(actionables []*Actionable) []ActionableRecord {
+	sanitizer := security.NewEnvSanitizer()
+	sanitizer.SetVisibilityLevel(security.VisibilityMasked)
+
+	records := make([]ActionableRecord, 0, len(actionables))
+	for _, actionable := range actionables {
+		// Mask sensitive values before they can leak through the command
+		commandEnv := make(map[string]string, len(actionable.Environment))
+		exported := make(map[string]string)
+		for name, value := range actionable.Environment {
+			if sanitizer.IsSensitive(name) {
+				commandEnv[name] = sanitizer.SanitizeValue(name, value)
+				continue
+			}
+			commandEnv[name] = value
+			if inherited, ok := os.LookupEnv(name); !ok || inherited != value {
+				exported[name] = value
+			}
+		}
+
+		records = append(records, ActionableRecord{
+			UUID:        tp.getTaskString(actionable.Task, "uuid"),
+			ID:          tp.getTaskInt(actionable.Task, "id"),
+			Action:      actionable.Action.Name,
+			Annotation:  actionable.Text,
+			Entry:       actionable.Entry,
+			Command:     expandVariables(actionable.Action.Command, commandEnv, true),
+			Environment: exported,
+		})
+	}
+
+	return records
+}
+
+// WriteActionables writes records to w in the given format
+func WriteActionables(w io.Writer, records []ActionableRecord, format ListFormat) error {
+	switch format {
+	case ListFormatNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case ListFormatTSV:
+		if _, err := fmt.Fprintln(w, "uuid\tid\taction\tannotation\tentry\tcommand"); err != nil {
+			return err
+		}
+		for _, r := range records {
+			fields := []string{r.UUID, fmt.Sprintf("%d", r.ID), r.Action, r.Annotation, r.Entry, r.Command}
+			for i, field := range fields {
+				fields[i] = escapeTSV(field)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	}
+}
+
+// tsvEscaper escapes characters that would break the TSV layout
+var tsvEscaper = strings.NewReplacer("\\", `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func escapeTSV(field string) string {
+	return tsvEscaper.Replace(field)
+}
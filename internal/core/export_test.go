@@ -0,0 +1,128 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func TestParseListFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ListFormat
+		wantErr bool
+	}{
+		{"", ListFormatJSON, false},
+		{"json", ListFormatJSON, false},
+		{"NDJSON", ListFormatNDJSON, false},
+		{"tsv", ListFormatTSV, false},
+		{"csv", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseListFormat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseListFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseListFormat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestActionableRecords(t *testing.T) {
+	tp := NewTaskProcessor(config.DefaultConfig())
+	actionables := []*Actionable{{
+		Text:  "https://example.com",
+		Task:  map[string]any{"uuid": "abc-123", "id": float64(7)},
+		Entry: "20240101T000000Z",
+		Action: types.Action{
+			Name:    "url",
+			Command: "open $FILE --token $API_TOKEN",
+		},
+		Environment: map[string]string{
+			"FILE":      "https://example.com",
+			"API_TOKEN": "supersecretvalue",
+		},
+	}}
+
+	records := tp.ActionableRecords(actionables)
+	if len(records) != 1 {
+		t.Fatalf("ActionableRecords() returned %d records, want 1", len(records))
+	}
+
+	record := records[0]
+	if record.UUID != "abc-123" || record.ID != 7 {
+		t.Errorf("record uuid/id = %q/%d, want abc-123/7", record.UUID, record.ID)
+	}
+	if strings.Contains(record.Command, "supersecretvalue") {
+		t.Errorf("Command leaks sensitive value: %q", record.Command)
+	}
+	if _, ok := record.Environment["API_TOKEN"]; ok {
+		t.Error("Environment should not contain sensitive variables")
+	}
+	if record.Environment["FILE"] != "https://example.com" {
+		t.Errorf("Environment[FILE] = %q, want https://example.com", record.Environment["FILE"])
+	}
+}
+
+func TestWriteActionables(t *testing.T) {
+	records := []ActionableRecord{
+		{UUID: "a", ID: 1, Action: "url", Annotation: "one\ttwo", Command: "open one"},
+		{UUID: "b", ID: 2, Action: "notes", Annotation: "line\nbreak", Command: "edit two"},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteActionables(&buf, records, ListFormatJSON); err != nil {
+			t.Fatalf("WriteActionables() error = %v", err)
+		}
+		var decoded []ActionableRecord
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("output is not a JSON array: %v", err)
+		}
+		if len(decoded) != 2 {
+			t.Errorf("decoded %d records, want 2", len(decoded))
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteActionables(&buf, records, ListFormatNDJSON); err != nil {
+			t.Fatalf("WriteActionables() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("got %d lines, want 2", len(lines))
+		}
+		for _, line := range lines {
+			var record ActionableRecord
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Errorf("line %q is not valid JSON: %v", line, err)
+			}
+		}
+	})
+
+	t.Run("tsv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteActionables(&buf, records, ListFormatTSV); err != nil {
+			t.Fatalf("WriteActionables() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("got %d lines, want 3", len(lines))
+		}
+		for _, line := range lines {
+			if fields := strings.Split(line, "\t"); len(fields) != 6 {
+				t.Errorf("line %q has %d fields, want 6", line, len(fields))
+			}
+		}
+		if !strings.Contains(lines[1], `one\ttwo`) {
+			t.Errorf("tab in annotation not escaped: %q", lines[1])
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+// executeActionable executes an actionable and, when its command fails, the
+// actions of its fallback chain in order until one succeeds. Every failed
+// attempt is reported with its reason.
+func (tp *TaskProcessor) executeActionable(ctx context.Context, actionable *Actionable) error {
+	chain := tp.fallbackChain(actionable)
+
+	var failures []string
+	var err error
+	for i, attempt := range chain {
+		if i > 0 {
+			tp.formatter.Info("Trying fallback action: %s", attempt.Action.Name)
+		}
+
+		if err = tp.runActionable(ctx, attempt); err == nil {
+			return nil
+		}
+
+		reason := failureReason(err)
+		failures = append(failures, fmt.Sprintf("%s: %s", attempt.Action.Name, reason))
+		if i < len(chain)-1 {
+			tp.formatter.Warning("Action %s failed: %s", attempt.Action.Name, reason)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(chain) == 1 {
+		return err
+	}
+	return errors.New(errors.ActionExecution, fmt.Sprintf("All %d attempts failed", len(failures))).
+		WithDetails(strings.Join(failures, "\n"))
+}
+
+// fallbackChain returns actionable followed by the actionables to try when
+// it fails: its configured fallback actions, then, with
+// fallback_to_next_match, the other actions that matched the same text.
+// Fallback actions run with the environment of the original match and each
+// action is tried at most once.
+func (tp *TaskProcessor) fallbackChain(actionable *Actionable) []*Actionable {
+	chain := []*Actionable{actionable}
+	tried := map[string]bool{actionable.Action.Name: true}
+
+	for _, name := range actionable.Action.Fallback {
+		i := slices.IndexFunc(tp.config.Actions, func(action types.Action) bool { return action.Name == name })
+		if i < 0 || tried[name] {
+			continue
+		}
+		tried[name] = true
+
+		fallback := *actionable
+		fallback.Action = tp.config.Actions[i]
+		fallback.alternatives = nil
+		chain = append(chain, &fallback)
+	}
+
+	if tp.config.General.FallbackToNextMatch {
+		for _, alternative := range actionable.alternatives {
+			if !tried[alternative.Action.Name] {
+				tried[alternative.Action.Name] = true
+				chain = append(chain, alternative)
+			}
+		}
+	}
+
+	return chain
+}
+
+// failureReason summarizes why an attempt failed in one line
+func failureReason(err error) string {
+	if taskErr, ok := err.(*errors.TaskopenError); ok && taskErr.Cause != nil {
+		return firstLine(taskErr.Message + ": " + taskErr.Cause.Error())
+	} else if ok {
+		return firstLine(taskErr.Message)
+	}
+	return firstLine(err.Error())
+}
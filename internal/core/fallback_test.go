@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func TestExecuteActionable_Fallback(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+
+	tp := newTestProcessor([]types.Action{
+		{Name: "viewer", Target: "annotations", Regex: `.*`, Command: "false", Fallback: []string{"broken", "opener"}},
+		{Name: "broken", Target: "annotations", Regex: `.*`, Command: "false"},
+		{Name: "opener", Target: "annotations", Regex: `.*`, Command: "touch " + marker},
+	})
+
+	actionable := &Actionable{Text: "a.pdf", Action: tp.config.Actions[0], Environment: map[string]string{}}
+	if err := tp.executeActionable(context.Background(), actionable); err != nil {
+		t.Fatalf("executeActionable() error = %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("fallback action did not run: %v", err)
+	}
+}
+
+func TestExecuteActionable_FallbackReport(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "viewer", Target: "annotations", Regex: `.*`, Command: "false", Fallback: []string{"printer"}},
+		{Name: "printer", Target: "annotations", Regex: `.*`, Command: "sh -c 'exit 3'"},
+	})
+
+	actionable := &Actionable{Text: "a.pdf", Action: tp.config.Actions[0], Environment: map[string]string{}}
+	err := tp.executeActionable(context.Background(), actionable)
+	if err == nil {
+		t.Fatal("executeActionable() succeeded, want error")
+	}
+
+	taskErr, ok := err.(*errors.TaskopenError)
+	if !ok {
+		t.Fatalf("error type = %T, want *errors.TaskopenError", err)
+	}
+	for _, want := range []string{"viewer: Command exited with code 1", "printer: Command exited with code 3"} {
+		if !strings.Contains(taskErr.Details, want) {
+			t.Errorf("details %q do not contain %q", taskErr.Details, want)
+		}
+	}
+}
+
+func TestExecuteActionable_FallbackToNextMatch(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+
+	tp := newTestProcessor([]types.Action{
+		{Name: "viewer", Target: "annotations", Regex: `\.pdf$`, Command: "false"},
+		{Name: "opener", Target: "annotations", Regex: `.*`, Command: "touch " + marker},
+	})
+	tp.config.General.FallbackToNextMatch = true
+
+	tasks := []map[string]any{
+		{"uuid": "a", "annotations": []any{map[string]any{"description": "~/a.pdf"}}},
+	}
+	actionables, err := tp.findActionableItems(context.Background(), tasks, tp.actions, true)
+	if err != nil {
+		t.Fatalf("findActionableItems() error = %v", err)
+	}
+	if len(actionables) != 1 || actionables[0].Action.Name != "viewer" {
+		t.Fatalf("actionables = %v, want only viewer", actionables)
+	}
+
+	if err := tp.executeActionable(context.Background(), actionables[0]); err != nil {
+		t.Fatalf("executeActionable() error = %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("next matching action did not run: %v", err)
+	}
+}
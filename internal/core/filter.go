@@ -0,0 +1,220 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+	"github.com/johnconnor-sec/taskopen-go/internal/exec"
+)
+
+// defaultFilterWorkers is used when filter_workers is not configured
+const defaultFilterWorkers = 4
+
+// filterJob is one filter command to evaluate
+type filterJob struct {
+	command string
+	env     map[string]string
+}
+
+// applyFilters evaluates the filter commands of the matched candidates and
+// returns those that pass, in their original order. Each group holds the
+// candidates for one matched text in action order; in single mode only the
+// first passing candidate of a group is kept. Each kept candidate records the
+// passing candidates after it as its alternatives.
+func (tp *TaskProcessor) applyFilters(ctx context.Context, groups [][]*Actionable, single bool) ([]*Actionable, error) {
+	// Without fallback the alternatives are never used, so there is no need
+	// to run the filters of candidates after the first passing one
+	if single && !tp.config.General.FallbackToNextMatch {
+		return tp.applyFirstFilters(ctx, groups)
+	}
+
+	var jobs []filterJob
+	jobIndex := make(map[*Actionable]int)
+	seen := make(map[string]int)
+
+	for _, group := range groups {
+		for _, candidate := range group {
+			if candidate.Action.FilterCommand == "" {
+				continue
+			}
+
+			command := expandVariables(candidate.Action.FilterCommand, candidate.Environment, true)
+			if i, ok := seen[command]; ok && tp.config.General.FilterCache {
+				jobIndex[candidate] = i
+				continue
+			}
+
+			seen[command] = len(jobs)
+			jobIndex[candidate] = len(jobs)
+			jobs = append(jobs, filterJob{command: command, env: candidate.Environment})
+		}
+	}
+
+	passed, err := tp.runFilters(ctx, jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	var actionables []*Actionable
+	for _, group := range groups {
+		var passing []*Actionable
+		for _, candidate := range group {
+			if i, ok := jobIndex[candidate]; ok && !passed[i] {
+				tp.logFilteredOut(candidate)
+				continue
+			}
+			passing = append(passing, candidate)
+		}
+
+		for i, candidate := range passing {
+			candidate.alternatives = passing[i+1:]
+			actionables = append(actionables, candidate)
+			if single {
+				break
+			}
+		}
+	}
+
+	return actionables, nil
+}
+
+// applyFirstFilters keeps the first passing candidate of each group. The
+// groups are evaluated in rounds: each round runs the filter of the next
+// candidate of every undecided group on the shared worker pool, and a group
+// is decided as soon as one of its candidates passes.
+func (tp *TaskProcessor) applyFirstFilters(ctx context.Context, groups [][]*Actionable) ([]*Actionable, error) {
+	chosen := make([]*Actionable, len(groups))
+	next := make([]int, len(groups))
+	done := make([]bool, len(groups))
+	cached := make(map[string]bool)
+	useCache := tp.config.General.FilterCache
+
+	for {
+		var jobs []filterJob
+		var waiting [][]int
+		jobIndex := make(map[string]int)
+
+		for g, group := range groups {
+			for !done[g] {
+				if next[g] >= len(group) {
+					done[g] = true
+					break
+				}
+
+				candidate := group[next[g]]
+				if candidate.Action.FilterCommand == "" {
+					chosen[g], done[g] = candidate, true
+					break
+				}
+
+				command := expandVariables(candidate.Action.FilterCommand, candidate.Environment, true)
+				if passed, ok := cached[command]; ok && useCache {
+					if passed {
+						chosen[g], done[g] = candidate, true
+					} else {
+						tp.logFilteredOut(candidate)
+						next[g]++
+					}
+					continue
+				}
+
+				if i, ok := jobIndex[command]; ok && useCache {
+					waiting[i] = append(waiting[i], g)
+				} else {
+					jobIndex[command] = len(jobs)
+					jobs = append(jobs, filterJob{command: command, env: candidate.Environment})
+					waiting = append(waiting, []int{g})
+				}
+				break
+			}
+		}
+
+		if len(jobs) == 0 {
+			break
+		}
+
+		passed, err := tp.runFilters(ctx, jobs)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, job := range jobs {
+			cached[job.command] = passed[i]
+			for _, g := range waiting[i] {
+				if passed[i] {
+					chosen[g], done[g] = groups[g][next[g]], true
+				} else {
+					tp.logFilteredOut(groups[g][next[g]])
+					next[g]++
+				}
+			}
+		}
+	}
+
+	var actionables []*Actionable
+	for _, candidate := range chosen {
+		if candidate != nil {
+			actionables = append(actionables, candidate)
+		}
+	}
+	return actionables, nil
+}
+
+// logFilteredOut records that a candidate's filter command rejected it
+func (tp *TaskProcessor) logFilteredOut(candidate *Actionable) {
+	tp.logger.Info("Filter command filtered out action", map[string]any{
+		"action": candidate.Action.Name,
+		"text":   candidate.Text,
+	})
+}
+
+// runFilters runs jobs on a bounded pool of workers and reports which of
+// them passed. It stops handing out jobs once ctx is cancelled.
+func (tp *TaskProcessor) runFilters(ctx context.Context, jobs []filterJob) ([]bool, error) {
+	passed := make([]bool, len(jobs))
+	if len(jobs) == 0 {
+		return passed, nil
+	}
+
+	workers := tp.config.General.FilterWorkers
+	if workers <= 0 {
+		workers = defaultFilterWorkers
+	}
+	workers = min(workers, len(jobs))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				passed[i] = tp.executeFilter(ctx, jobs[i].command, jobs[i].env)
+			}
+		}()
+	}
+
+dispatch:
+	for i := range jobs {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ActionExecution, "Filter evaluation cancelled")
+	}
+	return passed, nil
+}
+
+// executeFilter runs an expanded filter command and returns whether it passed
+func (tp *TaskProcessor) executeFilter(ctx context.Context, command string, env map[string]string) bool {
+	result, err := tp.executor.Execute(ctx, "sh", []string{"-c", command},
+		&exec.ExecutionOptions{Environment: env})
+	return err == nil && result.ExitCode == 0
+}
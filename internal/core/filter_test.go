@@ -0,0 +1,153 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func TestFindActionableItems_Filters(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "rejected", Target: "annotations", Regex: `.*`, FilterCommand: "false"},
+		{Name: "accepted", Target: "annotations", Regex: `.*`, FilterCommand: "test -n $FILE"},
+		{Name: "plain", Target: "annotations", Regex: `.*`},
+	})
+
+	tasks := []map[string]any{
+		{"uuid": "a", "annotations": []any{map[string]any{"description": "one"}}},
+		{"uuid": "b", "annotations": []any{map[string]any{"description": "two"}}},
+	}
+
+	tests := []struct {
+		name   string
+		single bool
+		want   []string
+	}{
+		{"all", false, []string{"accepted:one", "plain:one", "accepted:two", "plain:two"}},
+		{"single", true, []string{"accepted:one", "accepted:two"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionables, err := tp.findActionableItems(context.Background(), tasks, tp.actions, tt.single)
+			if err != nil {
+				t.Fatalf("findActionableItems() error = %v", err)
+			}
+
+			var got []string
+			for _, actionable := range actionables {
+				got = append(got, actionable.Action.Name+":"+actionable.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("actionables = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindActionableItems_FilterCache(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "runs")
+
+	tasks := []map[string]any{
+		{"uuid": "a", "annotations": []any{map[string]any{"description": "one"}}},
+		{"uuid": "b", "annotations": []any{map[string]any{"description": "two"}}},
+		{"uuid": "c", "annotations": []any{map[string]any{"description": "three"}}},
+	}
+
+	tests := []struct {
+		name  string
+		cache bool
+		runs  int
+	}{
+		{"without cache", false, 3},
+		{"with cache", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(log)
+
+			tp := newTestProcessor([]types.Action{
+				{Name: "logged", Target: "annotations", Regex: `.*`, FilterCommand: "echo run >> " + log},
+			})
+			tp.config.General.FilterCache = tt.cache
+
+			actionables, err := tp.findActionableItems(context.Background(), tasks, tp.actions, false)
+			if err != nil {
+				t.Fatalf("findActionableItems() error = %v", err)
+			}
+			if len(actionables) != 3 {
+				t.Errorf("got %d actionables, want 3", len(actionables))
+			}
+
+			data, err := os.ReadFile(log)
+			if err != nil {
+				t.Fatalf("reading filter log: %v", err)
+			}
+			if runs := strings.Count(string(data), "run"); runs != tt.runs {
+				t.Errorf("filter ran %d times, want %d", runs, tt.runs)
+			}
+		})
+	}
+}
+
+func TestFindActionableItems_SingleStopsAtFirstPass(t *testing.T) {
+	dir := t.TempDir()
+	tp := newTestProcessor([]types.Action{
+		{Name: "first", Target: "annotations", Regex: `.*`, FilterCommand: "echo $FILE >> " + filepath.Join(dir, "first") + "; test $FILE = two"},
+		{Name: "second", Target: "annotations", Regex: `.*`, FilterCommand: "echo $FILE >> " + filepath.Join(dir, "second")},
+		{Name: "third", Target: "annotations", Regex: `.*`, FilterCommand: "echo $FILE >> " + filepath.Join(dir, "third")},
+	})
+
+	tasks := []map[string]any{
+		{"uuid": "a", "annotations": []any{map[string]any{"description": "one"}}},
+		{"uuid": "b", "annotations": []any{map[string]any{"description": "two"}}},
+	}
+
+	actionables, err := tp.findActionableItems(context.Background(), tasks, tp.actions, true)
+	if err != nil {
+		t.Fatalf("findActionableItems() error = %v", err)
+	}
+
+	var got []string
+	for _, actionable := range actionables {
+		got = append(got, actionable.Action.Name+":"+actionable.Text)
+	}
+	if want := []string{"second:one", "first:two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("actionables = %q, want %q", got, want)
+	}
+
+	// Filters of the same round run concurrently, so compare sorted runs
+	runs := map[string][]string{"first": {"one", "two"}, "second": {"one"}, "third": nil}
+	for name, want := range runs {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		got := strings.Fields(string(data))
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s filter ran for %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFindActionableItems_FilterCancelled(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "slow", Target: "annotations", Regex: `.*`, FilterCommand: "sleep 5"},
+	})
+
+	tasks := []map[string]any{
+		{"uuid": "a", "annotations": []any{map[string]any{"description": "one"}}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := tp.findActionableItems(ctx, tasks, tp.actions, false); err == nil {
+		t.Error("findActionableItems() with cancelled context succeeded, want error")
+	}
+}
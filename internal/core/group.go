@@ -0,0 +1,80 @@
+package core
+
+import (
+	"slices"
+	"strings"
+)
+
+// groupDuplicates merges actionables whose expanded commands are identical
+// into the first of them, which then runs once for all related tasks. Every
+// remaining actionable gets $UUIDS and $IDS holding the space-separated
+// identifiers of its tasks.
+func (tp *TaskProcessor) groupDuplicates(actionables []*Actionable) []*Actionable {
+	var grouped []*Actionable
+	byCommand := make(map[string]*Actionable)
+
+	for _, actionable := range actionables {
+		command := expandVariables(actionable.Action.Command, actionable.Environment, true)
+		if first, ok := byCommand[command]; ok {
+			first.related = append(first.related, actionable)
+			continue
+		}
+
+		actionable.related = []*Actionable{actionable}
+		byCommand[command] = actionable
+		grouped = append(grouped, actionable)
+	}
+
+	for _, actionable := range grouped {
+		var uuids, ids []string
+		for _, task := range actionable.relatedTasks() {
+			if uuid := tp.getTaskString(task, "uuid"); uuid != "" {
+				uuids = append(uuids, uuid)
+			}
+			// Tasks outside the working set have ID 0
+			if id := tp.getTaskInt(task, "id"); id != 0 {
+				ids = append(ids, tp.getTaskString(task, "id"))
+			}
+		}
+		actionable.Environment["UUIDS"] = strings.Join(uuids, " ")
+		actionable.Environment["IDS"] = strings.Join(ids, " ")
+	}
+
+	return grouped
+}
+
+// relatedTasks returns the distinct tasks an actionable stands for, in the
+// order they were found
+func (a *Actionable) relatedTasks() []map[string]any {
+	members := a.related
+	if len(members) == 0 {
+		members = []*Actionable{a}
+	}
+
+	var tasks []map[string]any
+	var seen []string
+	for _, member := range members {
+		if member.Task == nil {
+			continue
+		}
+		uuid, _ := member.Task["uuid"].(string)
+		if uuid != "" && slices.Contains(seen, uuid) {
+			continue
+		}
+		seen = append(seen, uuid)
+		tasks = append(tasks, member.Task)
+	}
+	return tasks
+}
+
+// taskDescriptions returns the descriptions of the tasks an actionable
+// stands for
+func (a *Actionable) taskDescriptions() []string {
+	var descriptions []string
+	for _, task := range a.relatedTasks() {
+		if desc, ok := task["description"].(string); ok && desc != "" {
+			descriptions = append(descriptions, desc)
+		}
+	}
+	return descriptions
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func TestGroupDuplicates(t *testing.T) {
+	tp := newTestProcessor(nil)
+
+	open := types.Action{Name: "open", Command: "xdg-open $FILE"}
+	edit := types.Action{Name: "edit", Command: "vim $FILE"}
+	taskA := map[string]any{"uuid": "uuid-a", "id": float64(1), "description": "first"}
+	taskB := map[string]any{"uuid": "uuid-b", "id": float64(0), "description": "second"}
+	taskC := map[string]any{"uuid": "uuid-c", "id": float64(3), "description": "third"}
+
+	newActionable := func(action types.Action, task map[string]any, file string) *Actionable {
+		return &Actionable{Text: file, Action: action, Task: task, Environment: map[string]string{"FILE": file}}
+	}
+
+	actionables := []*Actionable{
+		newActionable(open, taskA, "~/spec.pdf"),
+		newActionable(edit, taskA, "~/spec.pdf"),
+		newActionable(open, taskB, "~/spec.pdf"),
+		newActionable(open, taskC, "~/other.pdf"),
+		newActionable(open, taskC, "~/spec.pdf"),
+	}
+
+	grouped := tp.groupDuplicates(actionables)
+	if len(grouped) != 3 {
+		t.Fatalf("groupDuplicates() returned %d actionables, want 3", len(grouped))
+	}
+
+	tests := []struct {
+		actionable *Actionable
+		uuids      string
+		ids        string
+		tasks      int
+	}{
+		{grouped[0], "uuid-a uuid-b uuid-c", "1 3", 3},
+		{grouped[1], "uuid-a", "1", 1},
+		{grouped[2], "uuid-c", "3", 1},
+	}
+
+	for _, tt := range tests {
+		env := tt.actionable.Environment
+		if env["UUIDS"] != tt.uuids || env["IDS"] != tt.ids {
+			t.Errorf("%s %s: UUIDS=%q IDS=%q, want %q and %q",
+				tt.actionable.Action.Name, tt.actionable.Text, env["UUIDS"], env["IDS"], tt.uuids, tt.ids)
+		}
+		if got := len(tt.actionable.taskDescriptions()); got != tt.tasks {
+			t.Errorf("%s %s: %d related tasks, want %d", tt.actionable.Action.Name, tt.actionable.Text, got, tt.tasks)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/exec"
+)
+
+const (
+	// inlineTimeout bounds how long a single inline command may run
+	inlineTimeout = 2 * time.Second
+
+	// inlineWorkers limits how many inline commands run at the same time
+	inlineWorkers = 4
+
+	// inlineMaxLines limits how much inline output is kept per actionable
+	inlineMaxLines = 10
+)
+
+// inlineCache remembers inline command output by expanded command
+type inlineCache struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func newInlineCache() *inlineCache {
+	return &inlineCache{entries: make(map[string]string)}
+}
+
+func (c *inlineCache) get(command string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.entries[command]
+	return value, ok
+}
+
+func (c *inlineCache) set(command, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[command] = value
+}
+
+// resolveInlineOutputs runs the inline commands of all actionables and stores
+// their output on the actionable for display. No further commands are started
+// once ctx is cancelled.
+func (tp *TaskProcessor) resolveInlineOutputs(ctx context.Context, actionables []*Actionable) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, inlineWorkers)
+
+dispatch:
+	for _, actionable := range actionables {
+		if actionable.Action.InlineCommand == "" {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func(a *Actionable) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			a.Inline = tp.runInlineCommand(ctx, a)
+		}(actionable)
+	}
+
+	wg.Wait()
+}
+
+// runInlineCommand executes an actionable's inline command and returns its
+// trimmed output. Failures are logged and produce an empty string.
+func (tp *TaskProcessor) runInlineCommand(ctx context.Context, actionable *Actionable) string {
+	command := expandVariables(actionable.Action.InlineCommand, actionable.Environment, true)
+
+	if cached, ok := tp.inlineCache.get(command); ok {
+		return cached
+	}
+
+	result, err := tp.executor.Execute(ctx, "sh", []string{"-c", command}, &exec.ExecutionOptions{
+		Environment:   actionable.Environment,
+		CaptureOutput: true,
+		Timeout:       inlineTimeout,
+	})
+
+	output := ""
+	switch {
+	case err != nil:
+		tp.logger.Debug("Inline command failed", map[string]any{
+			"action":  actionable.Action.Name,
+			"command": command,
+			"error":   err.Error(),
+		})
+	case result.ExitCode != 0:
+		tp.logger.Debug("Inline command exited with non-zero code", map[string]any{
+			"action":    actionable.Action.Name,
+			"command":   command,
+			"exit_code": result.ExitCode,
+		})
+	default:
+		output = trimInlineOutput(result.Stdout)
+	}
+
+	tp.inlineCache.set(command, output)
+	return output
+}
+
+// trimInlineOutput strips surrounding whitespace and keeps at most inlineMaxLines lines
+func trimInlineOutput(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > inlineMaxLines {
+		lines = lines[:inlineMaxLines]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// firstLine returns the first line of text
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
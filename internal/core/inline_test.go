@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func newInlineActionable(command string, env map[string]string) *Actionable {
+	return &Actionable{
+		Action:      types.Action{Name: "inline", InlineCommand: command},
+		Environment: env,
+	}
+}
+
+func TestResolveInlineOutputs(t *testing.T) {
+	tp := newTestProcessor(nil)
+
+	withOutput := newInlineActionable(`printf '  %s\n' "$FILE"`, map[string]string{"FILE": "a b.pdf"})
+	failing := newInlineActionable("printf 'partial'; exit 3", map[string]string{})
+	withoutCommand := newInlineActionable("", map[string]string{})
+
+	tp.resolveInlineOutputs(context.Background(), []*Actionable{withOutput, failing, withoutCommand})
+
+	if withOutput.Inline != "a b.pdf" {
+		t.Errorf("Inline = %q, want the trimmed output %q", withOutput.Inline, "a b.pdf")
+	}
+	if failing.Inline != "" {
+		t.Errorf("Inline of failing command = %q, want empty", failing.Inline)
+	}
+	if withoutCommand.Inline != "" {
+		t.Errorf("Inline without command = %q, want empty", withoutCommand.Inline)
+	}
+}
+
+func TestResolveInlineOutputs_TrimsLines(t *testing.T) {
+	tp := newTestProcessor(nil)
+	actionable := newInlineActionable("printf 'line %s\\n' 1 2 3 4 5 6 7 8 9 10 11 12", map[string]string{})
+
+	tp.resolveInlineOutputs(context.Background(), []*Actionable{actionable})
+
+	lines := strings.Split(actionable.Inline, "\n")
+	if len(lines) != inlineMaxLines || lines[0] != "line 1" || lines[inlineMaxLines-1] != "line 10" {
+		t.Errorf("Inline = %q, want lines 1 to %d", actionable.Inline, inlineMaxLines)
+	}
+}
+
+func TestResolveInlineOutputs_Cache(t *testing.T) {
+	tp := newTestProcessor(nil)
+	counter := filepath.Join(t.TempDir(), "runs")
+	command := "printf x >> " + counter + "; printf ok"
+
+	first := newInlineActionable(command, map[string]string{})
+	second := newInlineActionable(command, map[string]string{})
+	tp.resolveInlineOutputs(context.Background(), []*Actionable{first})
+	tp.resolveInlineOutputs(context.Background(), []*Actionable{second})
+
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(runs) != "x" {
+		t.Errorf("command ran %d times, want once", len(runs))
+	}
+	if first.Inline != "ok" || second.Inline != "ok" {
+		t.Errorf("Inline = %q and %q, want ok for both", first.Inline, second.Inline)
+	}
+}
+
+func TestResolveInlineOutputs_Timeout(t *testing.T) {
+	tp := newTestProcessor(nil)
+	actionable := newInlineActionable("printf early; exec sleep 10", map[string]string{})
+
+	start := time.Now()
+	tp.resolveInlineOutputs(context.Background(), []*Actionable{actionable})
+
+	if elapsed := time.Since(start); elapsed > inlineTimeout+time.Second {
+		t.Errorf("inline command ran for %v, want it stopped after %v", elapsed, inlineTimeout)
+	}
+	if actionable.Inline != "" {
+		t.Errorf("Inline = %q, want empty after timeout", actionable.Inline)
+	}
+}
+
+func TestResolveInlineOutputs_Cancelled(t *testing.T) {
+	tp := newTestProcessor(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var actionables []*Actionable
+	for range inlineWorkers * 2 {
+		actionables = append(actionables, newInlineActionable("exec sleep 10", map[string]string{}))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		tp.resolveInlineOutputs(ctx, actionables)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(inlineTimeout):
+		t.Fatal("resolveInlineOutputs() kept starting commands after cancellation")
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+)
+
+// linkRegex finds markdown links, angle-bracket URLs and bare URLs or paths.
+// Bare paths must start a word so that text like "and/or" is not a link.
+var linkRegex = regexp.MustCompile(
+	`\[([^\]]*)\]\(([^)\s]+)\)` +
+		`|<((?:[a-zA-Z][a-zA-Z0-9+.-]*://|www\.)[^>\s]+)>` +
+		`|((?:[a-zA-Z][a-zA-Z0-9+.-]*://|www\.)[^\s<>]+)` +
+		`|(?:^|[\s(])((?:~|\.{1,2})?/[^\s<>]*)`)
+
+// trailingPunctuation is stripped from bare links, where it usually belongs
+// to the surrounding sentence
+const trailingPunctuation = `.,;:!?'")`
+
+// trimTrailingPunctuation strips trailingPunctuation from a bare link. A
+// closing parenthesis is kept when it balances one inside the link, as in
+// https://en.wikipedia.org/wiki/Foo_(bar).
+func trimTrailingPunctuation(link string) string {
+	for link != "" {
+		last := link[len(link)-1]
+		if !strings.ContainsRune(trailingPunctuation, rune(last)) {
+			break
+		}
+		if last == ')' && strings.Count(link, "(") >= strings.Count(link, ")") {
+			break
+		}
+		link = link[:len(link)-1]
+	}
+	return link
+}
+
+// annotationLink is a path or URL found in an annotation
+type annotationLink struct {
+	raw    string // link as written in the annotation
+	target string // path or URL to act on
+	text   string // markdown link text, or the target itself
+}
+
+// extractLinks returns the links in the file part of an annotation in the
+// order they appear. Text without recognizable links, or with a single bare
+// link at its start, is returned whole as one link so that paths containing
+// spaces keep working.
+func extractLinks(text string) []annotationLink {
+	var links []annotationLink
+	bare := 0
+
+	for _, m := range linkRegex.FindAllStringSubmatchIndex(text, -1) {
+		switch {
+		case m[2] >= 0:
+			target := text[m[4]:m[5]]
+			links = append(links, annotationLink{raw: text[m[0]:m[1]], target: target, text: text[m[2]:m[3]]})
+		case m[6] >= 0:
+			target := text[m[6]:m[7]]
+			links = append(links, annotationLink{raw: text[m[0]:m[1]], target: target, text: target})
+		default:
+			start, end := m[8], m[9]
+			if start < 0 {
+				start, end = m[10], m[11]
+			}
+			target := trimTrailingPunctuation(text[start:end])
+			if target == "" || target == "/" {
+				continue
+			}
+			links = append(links, annotationLink{raw: target, target: target, text: target})
+			bare++
+		}
+	}
+
+	if len(links) == 0 || (len(links) == 1 && bare == 1 && strings.HasPrefix(text, links[0].raw)) {
+		return []annotationLink{{raw: text, target: text, text: text}}
+	}
+	return links
+}
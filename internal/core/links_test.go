@@ -0,0 +1,106 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func TestExtractLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []annotationLink
+	}{
+		{
+			name: "plain text",
+			text: "call the landlord",
+			want: []annotationLink{{"call the landlord", "call the landlord", "call the landlord"}},
+		},
+		{
+			name: "single path with spaces",
+			text: "~/My Docs/spec.pdf",
+			want: []annotationLink{{"~/My Docs/spec.pdf", "~/My Docs/spec.pdf", "~/My Docs/spec.pdf"}},
+		},
+		{
+			name: "markdown link and path",
+			text: "see [spec](https://x/spec) and ~/notes/a.md",
+			want: []annotationLink{
+				{"[spec](https://x/spec)", "https://x/spec", "spec"},
+				{"~/notes/a.md", "~/notes/a.md", "~/notes/a.md"},
+			},
+		},
+		{
+			name: "two URLs",
+			text: "https://a.example/x, www.b.example.",
+			want: []annotationLink{
+				{"https://a.example/x", "https://a.example/x", "https://a.example/x"},
+				{"www.b.example", "www.b.example", "www.b.example"},
+			},
+		},
+		{
+			name: "angle-bracket URL",
+			text: "ticket <https://jira.example/T-1>",
+			want: []annotationLink{{"<https://jira.example/T-1>", "https://jira.example/T-1", "https://jira.example/T-1"}},
+		},
+		{
+			name: "single bare link after text",
+			text: "draft is in (./draft.md)",
+			want: []annotationLink{{"./draft.md", "./draft.md", "./draft.md"}},
+		},
+		{
+			name: "balanced parentheses in URL",
+			text: "see https://en.wikipedia.org/wiki/Foo_(bar).",
+			want: []annotationLink{{"https://en.wikipedia.org/wiki/Foo_(bar)", "https://en.wikipedia.org/wiki/Foo_(bar)", "https://en.wikipedia.org/wiki/Foo_(bar)"}},
+		},
+		{
+			name: "URL in parentheses",
+			text: "(see https://en.wikipedia.org/wiki/Foo_(bar)) and (https://a.example/x)",
+			want: []annotationLink{
+				{"https://en.wikipedia.org/wiki/Foo_(bar)", "https://en.wikipedia.org/wiki/Foo_(bar)", "https://en.wikipedia.org/wiki/Foo_(bar)"},
+				{"https://a.example/x", "https://a.example/x", "https://a.example/x"},
+			},
+		},
+		{
+			name: "slash inside a word",
+			text: "decide and/or delegate",
+			want: []annotationLink{{"decide and/or delegate", "decide and/or delegate", "decide and/or delegate"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractLinks(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractLinks(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAnnotationLinks(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "url", Target: "annotations", Regex: `^https?://`},
+		{Name: "file", Target: "annotations", Regex: `^~/`},
+	})
+
+	groups := tp.matchAnnotationLinks(map[string]string{}, "see [spec](https://x/spec) and ~/notes/a.md", tp.actions, true)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+
+	want := []struct{ action, text, file, linkText string }{
+		{"url", "[spec](https://x/spec)", "https://x/spec", "spec"},
+		{"file", "~/notes/a.md", tp.expandPath("~/notes/a.md"), "~/notes/a.md"},
+	}
+	for i, w := range want {
+		if len(groups[i]) != 1 {
+			t.Fatalf("group %d has %d matches, want 1", i, len(groups[i]))
+		}
+		got := groups[i][0]
+		if got.Action.Name != w.action || got.Text != w.text || got.Environment["FILE"] != w.file || got.Environment["LINK_TEXT"] != w.linkText {
+			t.Errorf("group %d = {%s %q FILE=%q LINK_TEXT=%q}, want %+v",
+				i, got.Action.Name, got.Text, got.Environment["FILE"], got.Environment["LINK_TEXT"], w)
+		}
+	}
+}
@@ -1,76 +1,75 @@
 package core
 
 import (
-	"context"
-	"regexp"
+	"maps"
 
 	"github.com/johnconnor-sec/taskopen-go/internal/types"
 )
 
-// matchActionsLabel matches actions against annotation text with label support
-func (tp *TaskProcessor) matchActionsLabel(ctx context.Context, baseEnv map[string]string, text string, actions []types.Action, single bool) []*Actionable {
+// matchActionsLabel matches actions against annotation text with label
+// support and returns the matches of all links in the annotation.
+// Filter commands are run later by applyFilters.
+func (tp *TaskProcessor) matchActionsLabel(baseEnv map[string]string, text string, actions []compiledAction, single bool) []*Actionable {
 	var matches []*Actionable
+	for _, group := range tp.matchAnnotationLinks(baseEnv, text, actions, single) {
+		matches = append(matches, group...)
+	}
+	return matches
+}
 
+// matchAnnotationLinks splits an annotation into its label and links and
+// matches actions against each link separately, returning one group of
+// matches per link
+func (tp *TaskProcessor) matchAnnotationLinks(baseEnv map[string]string, text string, actions []compiledAction, single bool) [][]*Actionable {
 	// Split annotation into label and file part
-	splitRegex := regexp.MustCompile(`^((\S+):\s+)?(.*)$`)
-	splitMatches := splitRegex.FindStringSubmatch(text)
+	splitMatches := annotationSplitRegex.FindStringSubmatch(text)
 	if len(splitMatches) != 4 {
 		tp.logger.Error(
 			"Malformed annotation",
 			map[string]any{"text": text},
 		)
-		return matches
+		return nil
 	}
 
 	label := splitMatches[2]
-	file := splitMatches[3]
+	links := extractLinks(splitMatches[3])
 
-	for _, action := range actions {
-		env := tp.copyEnvironment(baseEnv)
-
-		// Check label regex
-		if action.LabelRegex != "" {
-			labelRegex, err := regexp.Compile(action.LabelRegex)
-			if err != nil {
-				tp.logger.Error("Invalid label regex", map[string]any{"regex": action.LabelRegex, "error": err.Error()})
-				continue
-			}
-			if !labelRegex.MatchString(label) {
-				continue
-			}
+	groups := make([][]*Actionable, 0, len(links))
+	for _, link := range links {
+		// Tell several links of one annotation apart in listings
+		display := text
+		if len(links) > 1 {
+			display = link.raw
 		}
+		groups = append(groups, tp.matchLink(baseEnv, text, display, label, link, actions, single))
+	}
+	return groups
+}
 
-		// Check file regex
-		fileRegex, err := regexp.Compile(action.Regex)
-		if err != nil {
-			tp.logger.Error("Invalid file regex", map[string]any{"regex": action.Regex, "error": err.Error()})
+// matchLink matches actions against a single link of an annotation
+func (tp *TaskProcessor) matchLink(baseEnv map[string]string, annotation, display, label string, link annotationLink, actions []compiledAction, single bool) []*Actionable {
+	var matches []*Actionable
+
+	for _, action := range actions {
+		// Check label regex
+		if action.labelRegex != nil && !action.labelRegex.MatchString(label) {
 			continue
 		}
 
-		fileMatches := fileRegex.FindStringSubmatch(file)
+		// Check file regex
+		fileMatches := action.regex.FindStringSubmatch(link.target)
 		if len(fileMatches) == 0 {
 			continue
 		}
 
 		// Set environment variables
-		env["LAST_MATCH"] = ""
-		if len(fileMatches) > 0 {
-			env["LAST_MATCH"] = fileMatches[0]
-		}
+		env := tp.copyEnvironment(baseEnv)
+		env["LAST_MATCH"] = fileMatches[0]
+		maps.Copy(env, types.MatchEnvironment(action.regex, fileMatches))
 		env["LABEL"] = label
-		env["FILE"] = tp.expandPath(file)
-		env["ANNOTATION"] = text
-
-		// Apply filter command if specified
-		if action.FilterCommand != "" {
-			if !tp.executeFilter(ctx, action.FilterCommand, env) {
-				tp.logger.Info("Filter command filtered out action", map[string]any{
-					"action": action.Name,
-					"text":   text,
-				})
-				continue
-			}
-		}
+		env["FILE"] = tp.expandPath(link.target)
+		env["LINK_TEXT"] = link.text
+		env["ANNOTATION"] = annotation
 
 		// Create actionable
 		taskID := env["UUID"]
@@ -79,15 +78,17 @@ func (tp *TaskProcessor) matchActionsLabel(ctx context.Context, baseEnv map[stri
 		}
 
 		actionable := &Actionable{
-			Text:        text,
+			Text:        display,
 			TaskID:      taskID,
-			Action:      action,
+			Action:      action.Action,
 			Environment: env,
 		}
 
 		matches = append(matches, actionable)
 
-		if single {
+		// Later actions are only needed if this one can be rejected by its
+		// filter or fall back to the next match
+		if single && action.FilterCommand == "" && !tp.config.General.FallbackToNextMatch {
 			break
 		}
 	}
@@ -96,29 +97,23 @@ func (tp *TaskProcessor) matchActionsLabel(ctx context.Context, baseEnv map[stri
 }
 
 // matchActionsPure matches actions against plain text (non-annotation attributes)
-func (tp *TaskProcessor) matchActionsPure(ctx context.Context, baseEnv map[string]string, text string, actions []types.Action, single bool) []*Actionable {
+func (tp *TaskProcessor) matchActionsPure(baseEnv map[string]string, text string, actions []compiledAction, single bool) []*Actionable {
 	var matches []*Actionable
 
 	for _, action := range actions {
-		env := tp.copyEnvironment(baseEnv)
-
 		// Check file regex
-		fileRegex, err := regexp.Compile(action.Regex)
-		if err != nil {
-			tp.logger.Error("Invalid regex", map[string]any{"regex": action.Regex, "error": err.Error()})
-			continue
-		}
-
-		fileMatches := fileRegex.FindStringSubmatch(text)
+		fileMatches := action.regex.FindStringSubmatch(text)
 		if len(fileMatches) == 0 {
 			continue
 		}
 
 		// Set environment variables
+		env := tp.copyEnvironment(baseEnv)
 		env["LAST_MATCH"] = ""
 		if len(fileMatches) > 0 {
 			env["LAST_MATCH"] = fileMatches[0]
 		}
+		maps.Copy(env, types.MatchEnvironment(action.regex, fileMatches))
 		env["FILE"] = text
 		env["ANNOTATION"] = text
 
@@ -129,17 +124,6 @@ func (tp *TaskProcessor) matchActionsPure(ctx context.Context, baseEnv map[strin
 			})
 		}
 
-		// Apply filter command if specified
-		if action.FilterCommand != "" {
-			if !tp.executeFilter(ctx, action.FilterCommand, env) {
-				tp.logger.Info("Filter command filtered out action", map[string]any{
-					"action": action.Name,
-					"text":   text,
-				})
-				continue
-			}
-		}
-
 		// Create actionable
 		taskID := env["UUID"]
 		if taskID == "" {
@@ -149,13 +133,15 @@ func (tp *TaskProcessor) matchActionsPure(ctx context.Context, baseEnv map[strin
 		actionable := &Actionable{
 			Text:        text,
 			TaskID:      taskID,
-			Action:      action,
+			Action:      action.Action,
 			Environment: env,
 		}
 
 		matches = append(matches, actionable)
 
-		if single {
+		// Later actions are only needed if this one can be rejected by its
+		// filter or fall back to the next match
+		if single && action.FilterCommand == "" && !tp.config.General.FallbackToNextMatch {
 			break
 		}
 	}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func newTestProcessor(actions []types.Action) *TaskProcessor {
+	cfg := config.DefaultConfig()
+	cfg.Actions = actions
+	return NewTaskProcessor(cfg)
+}
+
+func TestCompileActions_SkipsInvalidPatterns(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "good", Target: "annotations", Regex: `\.pdf$`},
+		{Name: "bad", Target: "annotations", Regex: `(`},
+		{Name: "badlabel", Target: "annotations", Regex: `.*`, LabelRegex: `[`},
+	})
+
+	if len(tp.actions) != 1 || tp.actions[0].Name != "good" {
+		t.Errorf("compiled actions = %v, want only good", tp.actions)
+	}
+}
+
+func TestMatchActionsLabel(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "pdf", Target: "annotations", Regex: `\.pdf$`, LabelRegex: `^doc$`},
+		{Name: "any", Target: "annotations", Regex: `.*`},
+	})
+
+	tests := []struct {
+		name   string
+		text   string
+		single bool
+		want   []string
+	}{
+		{"label and file match", "doc: ~/spec.pdf", false, []string{"pdf", "any"}},
+		{"single stops at first", "doc: ~/spec.pdf", true, []string{"pdf"}},
+		{"label mismatch", "notes: ~/spec.pdf", false, []string{"any"}},
+		{"no label", "~/spec.pdf", false, []string{"any"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := tp.matchActionsLabel(map[string]string{}, tt.text, tp.actions, tt.single)
+			var got []string
+			for _, match := range matches {
+				got = append(got, match.Action.Name)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("matchActionsLabel(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchActions_CaptureGroups(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "jira", Target: "annotations", Regex: `(?P<project>[A-Z]+)-(\d+)`},
+		{Name: "ticket", Target: "ticket", Regex: `(?P<key>\d+)`},
+	})
+
+	label := tp.matchActionsLabel(map[string]string{}, "bug: JIRA-42", tp.actions[:1], true)
+	if len(label) != 1 {
+		t.Fatalf("matchActionsLabel() returned %d matches, want 1", len(label))
+	}
+	env := label[0].Environment
+	if env["MATCH_1"] != "JIRA" || env["MATCH_PROJECT"] != "JIRA" || env["MATCH_2"] != "42" {
+		t.Errorf("annotation match environment = %v", env)
+	}
+
+	pure := tp.matchActionsPure(map[string]string{}, "ticket 7", tp.actions[1:], true)
+	if len(pure) != 1 {
+		t.Fatalf("matchActionsPure() returned %d matches, want 1", len(pure))
+	}
+	if env := pure[0].Environment; env["MATCH_1"] != "7" || env["MATCH_KEY"] != "7" {
+		t.Errorf("attribute match environment = %v", env)
+	}
+}
+
+// benchmarkActions returns n annotation actions of which only the last matches
+func benchmarkActions(n int) []types.Action {
+	actions := make([]types.Action, n)
+	for i := range actions {
+		actions[i] = types.Action{
+			Name:       fmt.Sprintf("action%d", i),
+			Target:     "annotations",
+			Regex:      fmt.Sprintf(`\.ext%d$`, i),
+			LabelRegex: ".*",
+			Command:    "true",
+		}
+	}
+	actions[n-1].Regex = `\.pdf$`
+	return actions
+}
+
+func BenchmarkMatchActionsLabel1000(b *testing.B) {
+	tp := newTestProcessor(benchmarkActions(1000))
+	env := map[string]string{"UUID": "abc", "ID": "1"}
+
+	b.ResetTimer()
+	for b.Loop() {
+		tp.matchActionsLabel(env, "doc: ~/spec.pdf", tp.actions, false)
+	}
+}
+
+func BenchmarkFindActionableItems(b *testing.B) {
+	tp := newTestProcessor(benchmarkActions(20))
+	ctx := context.Background()
+
+	tasks := make([]map[string]any, 2000)
+	for i := range tasks {
+		tasks[i] = map[string]any{
+			"id":          float64(i + 1),
+			"uuid":        fmt.Sprintf("uuid-%d", i),
+			"description": "task",
+			"annotations": []any{
+				map[string]any{"entry": "20240101T000000Z", "description": "doc: ~/spec.pdf"},
+				map[string]any{"entry": "20240101T000000Z", "description": "plain note"},
+			},
+		}
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := tp.findActionableItems(ctx, tasks, tp.actions, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCompileActions_Priority(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "first", Target: "annotations", Regex: `.*`},
+		{Name: "preferred", Target: "annotations", Regex: `.*`, Priority: 10},
+		{Name: "second", Target: "annotations", Regex: `.*`},
+		{Name: "fallback", Target: "annotations", Regex: `.*`, Priority: -1},
+	})
+
+	var got []string
+	for _, action := range tp.actions {
+		got = append(got, action.Name)
+	}
+	want := []string{"preferred", "first", "second", "fallback"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("action order = %v, want %v", got, want)
+	}
+
+	matches := tp.matchActionsLabel(map[string]string{}, "notes.txt", tp.actions, true)
+	if len(matches) != 1 || matches[0].Action.Name != "preferred" {
+		t.Errorf("single match = %v, want preferred", matches)
+	}
+}
+
+func TestFindActionableItems_When(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "work", Target: "annotations", Regex: `.*`, When: "+work and project.startswith:client"},
+		{Name: "always", Target: "annotations", Regex: `.*`},
+	})
+
+	tasks := []map[string]any{
+		{"uuid": "a", "tags": []any{"work"}, "project": "client.acme", "annotations": []any{map[string]any{"description": "one"}}},
+		{"uuid": "b", "tags": []any{"home"}, "annotations": []any{map[string]any{"description": "two"}}},
+	}
+
+	actionables, err := tp.findActionableItems(context.Background(), tasks, tp.actions, false)
+	if err != nil {
+		t.Fatalf("findActionableItems() error = %v", err)
+	}
+
+	var got []string
+	for _, actionable := range actionables {
+		got = append(got, actionable.Action.Name+":"+actionable.Text)
+	}
+	want := []string{"work:one", "always:one", "always:two"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("actionables = %v, want %v", got, want)
+	}
+}
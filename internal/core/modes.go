@@ -0,0 +1,38 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode selects how actionables are gathered and executed, mirroring the
+// subcommands of the original taskopen
+type Mode string
+
+const (
+	// ModeNormal offers the first matching action of each annotation in a menu
+	ModeNormal Mode = "normal"
+	// ModeAny offers every matching action of each annotation in a menu
+	ModeAny Mode = "any"
+	// ModeBatch runs the first matching action of each annotation without a menu
+	ModeBatch Mode = "batch"
+)
+
+// Modes lists all supported modes in display order
+var Modes = []Mode{ModeNormal, ModeAny, ModeBatch}
+
+// ParseMode converts a subcommand name into a Mode
+func ParseMode(name string) (Mode, error) {
+	for _, mode := range Modes {
+		if strings.EqualFold(name, string(mode)) {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown mode: %s", name)
+}
+
+// IsMode reports whether name is a mode subcommand
+func IsMode(name string) bool {
+	_, err := ParseMode(name)
+	return err == nil
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+	"github.com/johnconnor-sec/taskopen-go/internal/exec"
+	"github.com/johnconnor-sec/taskopen-go/internal/output"
+	"github.com/johnconnor-sec/taskopen-go/internal/security"
+	"github.com/johnconnor-sec/taskopen-go/internal/ui"
+)
+
+// handleNoActionables runs when the matched tasks have nothing actionable.
+// Depending on no_annotation_mode it runs the no_annotation_hook for a
+// single task or for each task, or prompts for annotations to add. The
+// prompt needs an interactive run; batch and non-interactive runs fall back
+// to the single mode so that scripts never wait for the terminal.
+func (tp *TaskProcessor) handleNoActionables(ctx context.Context, tasks []map[string]any, opts ProcessOptions) error {
+	mode := tp.config.General.NoAnnotationMode
+	if mode == config.NoAnnotationPrompt {
+		if opts.Interactive && opts.Mode != ModeBatch {
+			return tp.promptAnnotations(ctx, tasks)
+		}
+		tp.formatter.Warning("Not prompting for annotations in a non-interactive run")
+		mode = config.NoAnnotationSingle
+	}
+
+	if tp.config.General.NoAnnotationHook == "" {
+		return nil
+	}
+
+	if mode != config.NoAnnotationEach {
+		if len(tasks) != 1 {
+			return nil
+		}
+		tp.formatter.Warning("No actionable items found")
+		return tp.runNoAnnotationHook(ctx, tasks[0])
+	}
+
+	tp.formatter.Warning("No actionable items found in %d tasks", len(tasks))
+
+	failed := 0
+	for _, task := range tasks {
+		if err := tp.runNoAnnotationHook(ctx, task); err != nil {
+			failed++
+			tp.formatter.Error("%s: %s", tp.getTaskString(task, "description"), err.Error())
+		}
+	}
+
+	if failed > 0 {
+		return errors.New(errors.ActionExecution, fmt.Sprintf("no_annotation_hook failed for %d of %d tasks", failed, len(tasks)))
+	}
+	return nil
+}
+
+// tasksWithoutActionables returns the tasks that none of actionables
+// belongs to, in their original order
+func tasksWithoutActionables(tasks []map[string]any, actionables []*Actionable) []map[string]any {
+	covered := make(map[string]bool)
+	for _, actionable := range actionables {
+		covered[actionable.Environment["UUID"]] = true
+	}
+
+	var remaining []map[string]any
+	for _, task := range tasks {
+		if uuid, _ := task["uuid"].(string); !covered[uuid] {
+			remaining = append(remaining, task)
+		}
+	}
+	return remaining
+}
+
+// runNoAnnotationHook runs the no_annotation_hook in the environment of task
+func (tp *TaskProcessor) runNoAnnotationHook(ctx context.Context, task map[string]any) error {
+	hook := tp.config.General.NoAnnotationHook
+	result, err := tp.executor.Execute(ctx, "sh", []string{"-c", hook},
+		&exec.ExecutionOptions{Environment: tp.buildEnvironment(task)})
+	if err != nil {
+		tp.logger.Error("Failed executing no_annotation_hook", map[string]any{"command": hook, "error": err.Error()})
+		return errors.Wrap(err, errors.ActionExecution, "Failed to execute no_annotation_hook")
+	}
+
+	if result.ExitCode != 0 {
+		tp.logger.Error("no_annotation_hook exited with non-zero code", map[string]any{"command": hook, "exit_code": result.ExitCode})
+		return errors.New(errors.ActionExecution, fmt.Sprintf("no_annotation_hook failed with exit code %d", result.ExitCode))
+	}
+	return nil
+}
+
+// promptAnnotations lists tasks in the TUI and asks for an annotation to add
+// to each selected one. Empty or skipped annotations leave the task alone.
+func (tp *TaskProcessor) promptAnnotations(ctx context.Context, tasks []map[string]any) error {
+	items := make([]ui.MenuItem, len(tasks))
+	for i, task := range tasks {
+		items[i] = ui.MenuItem{
+			ID:          fmt.Sprintf("task-%d", i),
+			Text:        tp.getTaskString(task, "description"),
+			Description: tp.taskSummary(task),
+			Data:        map[string]any{"index": i},
+		}
+	}
+
+	menuConfig := ui.DefaultMenuConfig()
+	menuConfig.Title = "📝 Tasks Without Actionable Annotations"
+	menuConfig.ShowDescription = true
+	menuConfig.AllowSearch = true
+	menuConfig.VimMode = true
+	menuConfig.MaxItems = 15
+	menuConfig.AllowMultiSelect = true
+	menuConfig.CustomHelp = "Select the tasks to annotate"
+
+	tui, err := ui.NewSecureTUI(items, menuConfig, ui.SecureTUIConfig{
+		HideEnvVars:       true,
+		VisibilityLevel:   security.VisibilityMasked,
+		AccessibilityMode: output.AccessibilityNormal,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create secure TUI: %w", err)
+	}
+	defer tui.Close()
+
+	selected, err := tui.Show()
+	if err != nil {
+		return fmt.Errorf("TUI interaction failed: %w", err)
+	}
+	if len(selected) == 0 {
+		tp.formatter.Info("Annotation cancelled by user")
+		return nil
+	}
+
+	indices := make([]int, 0, len(selected))
+	for _, item := range selected {
+		data, ok := item.Data.(map[string]any)
+		if !ok {
+			return fmt.Errorf("selected item not found")
+		}
+		index, ok := data["index"].(int)
+		if !ok || index < 0 || index >= len(tasks) {
+			return fmt.Errorf("selected item not found")
+		}
+		indices = append(indices, index)
+	}
+
+	return tp.annotateSelected(ctx, tasks, indices, promptAnnotation)
+}
+
+// annotationPrompt asks for the annotation to add to the task described by
+// title. It returns false when the user skips the task.
+type annotationPrompt func(title string) (string, bool, error)
+
+// promptAnnotation is the annotationPrompt shown in the terminal
+func promptAnnotation(title string) (string, bool, error) {
+	return ui.Prompt("📝 Add Annotation", title, "Annotation: ", output.AccessibilityNormal)
+}
+
+// annotateSelected asks for an annotation for each selected task, given by
+// its index in tasks, and adds it. Empty or skipped annotations leave the
+// task alone.
+func (tp *TaskProcessor) annotateSelected(ctx context.Context, tasks []map[string]any, selected []int, prompt annotationPrompt) error {
+	for _, index := range selected {
+		task := tasks[index]
+		title := tp.getTaskString(task, "description")
+
+		annotation, confirmed, err := prompt(title)
+		if err != nil {
+			return fmt.Errorf("annotation prompt failed: %w", err)
+		}
+		annotation = strings.TrimSpace(annotation)
+		if !confirmed || annotation == "" {
+			tp.formatter.Info("Skipped task %s", title)
+			continue
+		}
+
+		uuid := tp.getTaskString(task, "uuid")
+		if uuid == "" {
+			return errors.New(errors.TaskwarriorQuery, "Matched task has no UUID")
+		}
+		if err := tp.annotateTask(ctx, uuid, annotation); err != nil {
+			return err
+		}
+		tp.formatter.Success("Annotated task %s: %s", title, annotation)
+	}
+
+	return nil
+}
+
+// taskSummary describes a task by its ID and project for menus
+func (tp *TaskProcessor) taskSummary(task map[string]any) string {
+	parts := []string{fmt.Sprintf("ID: %s", tp.getTaskString(task, "id"))}
+	if project := tp.getTaskString(task, "project"); project != "" {
+		parts = append(parts, fmt.Sprintf("Project: %s", project))
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/taskwarrior"
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func TestHandleNoActionables(t *testing.T) {
+	tasks := []map[string]any{
+		{"uuid": "a", "description": "first"},
+		{"uuid": "b", "description": "second"},
+	}
+
+	tests := []struct {
+		name  string
+		mode  string
+		tasks []map[string]any
+		want  string
+	}{
+		{"single task", config.NoAnnotationSingle, tasks[:1], "a\n"},
+		{"single skips several tasks", config.NoAnnotationSingle, tasks, ""},
+		{"default mode", "", tasks, ""},
+		{"each task", config.NoAnnotationEach, tasks, "a\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := filepath.Join(t.TempDir(), "hook.log")
+			tp := newTestProcessor(nil)
+			tp.config.General.NoAnnotationMode = tt.mode
+			tp.config.General.NoAnnotationHook = "echo $UUID >> " + log
+
+			if err := tp.handleNoActionables(context.Background(), tt.tasks, ProcessOptions{}); err != nil {
+				t.Fatalf("handleNoActionables() error = %v", err)
+			}
+
+			got, err := os.ReadFile(log)
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("hook ran for %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleNoActionables_EachReportsFailures(t *testing.T) {
+	tp := newTestProcessor(nil)
+	tp.config.General.NoAnnotationMode = config.NoAnnotationEach
+	tp.config.General.NoAnnotationHook = `test "$UUID" = a`
+
+	tasks := []map[string]any{{"uuid": "a"}, {"uuid": "b"}, {"uuid": "c"}}
+	err := tp.handleNoActionables(context.Background(), tasks, ProcessOptions{})
+	if err == nil || err.Error() != "no_annotation_hook failed for 2 of 3 tasks" {
+		t.Errorf("handleNoActionables() error = %v, want 2 of 3 failures", err)
+	}
+}
+
+func TestHandleNoActionables_PromptNeedsInteractiveRun(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ProcessOptions
+	}{
+		{"not interactive", ProcessOptions{Mode: ModeNormal}},
+		{"batch", ProcessOptions{Mode: ModeBatch, Interactive: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := filepath.Join(t.TempDir(), "hook.log")
+			tp := newTestProcessor(nil)
+			tp.config.General.NoAnnotationMode = config.NoAnnotationPrompt
+			tp.config.General.NoAnnotationHook = "echo $UUID >> " + log
+
+			// Reaching promptAnnotations would fail here, since tests have
+			// no terminal for the TUI
+			tasks := []map[string]any{{"uuid": "a", "description": "first"}}
+			if err := tp.handleNoActionables(context.Background(), tasks, tt.opts); err != nil {
+				t.Fatalf("handleNoActionables() error = %v", err)
+			}
+
+			got, err := os.ReadFile(log)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "a\n" {
+				t.Errorf("hook ran for %q, want the single mode fallback for a", got)
+			}
+		})
+	}
+}
+
+func TestProcessTasks_EachCoversTasksWithoutActionables(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "log")
+	cfg := config.DefaultConfig()
+	cfg.General.TaskBin = writeFakeTaskBin(t, `[{"id":1,"uuid":"a","description":"Review","annotations":[{"description":"~/spec.pdf"}]},
+ {"id":2,"uuid":"b","description":"Plan"}]`)
+	cfg.General.NoAnnotationMode = config.NoAnnotationEach
+	cfg.General.NoAnnotationHook = "echo hook $UUID >> " + log
+	cfg.Actions = []types.Action{{Name: "pdf", Target: "annotations", Regex: `\.pdf$`, Command: "echo action $UUID >> " + log}}
+	tp := NewTaskProcessor(cfg)
+
+	if err := tp.ProcessTasks(context.Background(), ProcessOptions{}); err != nil {
+		t.Fatalf("ProcessTasks() error = %v", err)
+	}
+
+	got, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hook b\naction a\n"; string(got) != want {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+}
+
+func TestAnnotateSelected(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "args")
+	taskBin := filepath.Join(dir, "task")
+	if err := os.WriteFile(taskBin, []byte("#!/bin/sh\necho \"$*\" >> "+log+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tp := newTestProcessor(nil)
+	tp.client = taskwarrior.NewClient(taskBin, nil, time.Second)
+
+	const (
+		uuidA = "11111111-1111-1111-1111-111111111111"
+		uuidB = "22222222-2222-2222-2222-222222222222"
+		uuidC = "33333333-3333-3333-3333-333333333333"
+	)
+	tasks := []map[string]any{
+		{"uuid": uuidA, "description": "first"},
+		{"uuid": uuidB, "description": "second"},
+		{"uuid": uuidC, "description": "third"},
+	}
+	answers := map[string]struct {
+		annotation string
+		confirmed  bool
+	}{
+		"first": {"  ~/notes.md ", true},
+		"third": {"ignored", false},
+	}
+
+	var asked []string
+	prompt := func(title string) (string, bool, error) {
+		asked = append(asked, title)
+		answer := answers[title]
+		return answer.annotation, answer.confirmed, nil
+	}
+
+	if err := tp.annotateSelected(context.Background(), tasks, []int{0, 2}, prompt); err != nil {
+		t.Fatalf("annotateSelected() error = %v", err)
+	}
+
+	if want := []string{"first", "third"}; !slices.Equal(asked, want) {
+		t.Errorf("prompted for %q, want %q", asked, want)
+	}
+
+	got, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(got)), "\n"); len(lines) != 1 ||
+		!strings.HasSuffix(lines[0], uuidA+" annotate -- ~/notes.md") {
+		t.Errorf("task ran with %q, want a single annotation of the first task", got)
+	}
+}
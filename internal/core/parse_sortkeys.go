@@ -1,33 +1,98 @@
 package core
 
-import "strings"
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
 
-// SortKey represents a sort field and direction
-type SortKey struct {
-	Key  string
-	Desc bool
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+	"github.com/johnconnor-sec/taskopen-go/internal/taskwarrior"
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+// parseSortKeys parses general.sort into sort keys, see types.ParseSortKeys
+func parseSortKeys(sortStr string) ([]types.SortKey, error) {
+	keys, err := types.ParseSortKeys(sortStr)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ValidationFailed, "Invalid sort order").
+			WithDetails(fmt.Sprintf("Sort: %s", sortStr)).
+			WithSuggestion(fmt.Sprintf("Use %s or a task attribute such as urgency, due or a UDA, optionally followed by + or -",
+				strings.Join(types.ActionableSortKeys, ", ")))
+	}
+	return keys, nil
+}
+
+// sortValue returns the value of actionable for a sort key
+func sortValue(actionable *Actionable, key string) any {
+	switch key {
+	case "annot":
+		return actionable.Text
+	case "annotation-entry":
+		return actionable.Entry
+	case "action":
+		return actionable.Action.Name
+	case "action.priority":
+		return float64(actionable.Action.Priority)
+	case "label":
+		return actionable.Environment["LABEL"]
+	}
+	return actionable.Task[key]
 }
 
-// parseSortKeys parses the sort string into sort keys
-func (tp *TaskProcessor) parseSortKeys(sortStr string) []SortKey {
-	var keys []SortKey
+// isMissingSortValue reports whether a sort value is absent or empty
+func isMissingSortValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
 
-	for field := range strings.SplitSeq(sortStr, ",") {
-		field = strings.TrimSpace(field)
-		if field == "" {
-			continue
-		}
+// compareSortValues compares two present sort values as numbers, then as
+// Taskwarrior dates, then as text
+func compareSortValues(a, b any) int {
+	aNum, aIsNum := sortNumber(a)
+	bNum, bIsNum := sortNumber(b)
+	if aIsNum && bIsNum {
+		return cmp.Compare(aNum, bNum)
+	}
 
-		desc := false
-		if strings.HasSuffix(field, "-") {
-			desc = true
-			field = field[:len(field)-1]
-		} else if strings.HasSuffix(field, "+") {
-			field = field[:len(field)-1]
+	aText, bText := sortText(a), sortText(b)
+	if aDate, err := taskwarrior.ParseTimestamp(aText); err == nil {
+		if bDate, err := taskwarrior.ParseTimestamp(bText); err == nil {
+			return aDate.Compare(bDate.Time)
 		}
+	}
+
+	return strings.Compare(aText, bText)
+}
 
-		keys = append(keys, SortKey{Key: field, Desc: desc})
+// sortNumber returns value as a number when it is numeric
+func sortNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, true
+		}
 	}
+	return 0, false
+}
 
-	return keys
+// sortText returns value as text, joining lists with commas
+func sortText(value any) string {
+	if list, ok := value.([]any); ok {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(value)
 }
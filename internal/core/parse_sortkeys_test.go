@@ -0,0 +1,51 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortActionables_Types(t *testing.T) {
+	tp := newTestProcessor(nil)
+
+	actionables := func() []*Actionable {
+		return []*Actionable{
+			{Text: "a", Entry: "20240105T000000Z", Task: map[string]any{"due": "20240301T000000Z", "estimate": float64(10)}, Environment: map[string]string{"LABEL": "doc"}},
+			{Text: "b", Entry: "20240101T000000Z", Task: map[string]any{"estimate": float64(9)}, Environment: map[string]string{}},
+			{Text: "c", Entry: "20240103T000000Z", Task: map[string]any{"due": "20240115T120000Z", "estimate": "11"}, Environment: map[string]string{"LABEL": "api"}},
+		}
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"due+", []string{"c", "a", "b"}},
+		{"due-", []string{"a", "c", "b"}},
+		{"estimate+", []string{"b", "a", "c"}},
+		{"estimate-", []string{"c", "a", "b"}},
+		{"annotation-entry", []string{"b", "c", "a"}},
+		{"label", []string{"c", "a", "b"}},
+		{"annot-", []string{"c", "b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			sortKeys, err := parseSortKeys(tt.sort)
+			if err != nil {
+				t.Fatalf("parseSortKeys(%q) error = %v", tt.sort, err)
+			}
+
+			items := actionables()
+			tp.sortActionables(items, sortKeys)
+
+			var got []string
+			for _, item := range items {
+				got = append(got, item.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sorted by %s = %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/johnconnor-sec/taskopen-go/internal/config"
 	"github.com/johnconnor-sec/taskopen-go/internal/errors"
 	"github.com/johnconnor-sec/taskopen-go/internal/exec"
 	"github.com/johnconnor-sec/taskopen-go/internal/output"
+	"github.com/johnconnor-sec/taskopen-go/internal/taskwarrior"
 )
 
 // TaskProcessor handles the main taskopen workflow
@@ -21,6 +23,14 @@ type TaskProcessor struct {
 	formatter      *output.Formatter
 	logger         *output.Logger
 	builtinHandler *BuiltinHandler
+	inlineCache    *inlineCache
+	client         *taskwarrior.Client
+
+	// actions holds the configured actions with their patterns compiled
+	actions []compiledAction
+
+	// udas maps UDA names to their taskrc types, loaded on first use
+	udas map[string]string
 }
 
 // NewTaskProcessor creates a new task processor
@@ -28,29 +38,62 @@ func NewTaskProcessor(cfg *config.Config) *TaskProcessor {
 	executor := exec.New(exec.ExecutionOptions{Timeout: 30 * time.Second})
 	formatter := output.NewFormatter(os.Stdout)
 	logger := output.NewLogger()
+	if cfg.General.Debug {
+		logger.SetLevel(output.LogLevelDebug)
+	}
 
-	return &TaskProcessor{
+	tp := &TaskProcessor{
 		config:         cfg,
 		executor:       executor,
 		formatter:      formatter,
 		logger:         logger,
 		builtinHandler: NewBuiltinHandler(executor, formatter, logger),
+		inlineCache:    newInlineCache(),
+		client:         taskwarrior.NewClient(cfg.General.TaskBin, cfg.General.TaskArgs, 10*time.Second),
 	}
+	tp.actions = tp.compileActions(cfg.Actions)
+
+	return tp
+}
+
+// ProcessOptions controls a single taskopen run
+type ProcessOptions struct {
+	// Filters are passed to taskwarrior to select tasks
+	Filters []string
+
+	// Mode selects which actions are offered and how they are executed
+	Mode Mode
+
+	// Single restricts each annotation to its first matching action.
+	// It is ignored in ModeAny, which always offers every match.
+	Single bool
+
+	// Interactive shows a menu when more than one actionable is found
+	Interactive bool
+
+	// Include limits candidate actions to these names when non-empty
+	Include []string
+
+	// Exclude removes these action names from the candidates
+	Exclude []string
+
+	// NoContext ignores the active Taskwarrior context
+	NoContext bool
+
+	// Execute replaces the command of every matched action when non-empty.
+	// Each annotation then yields a single actionable.
+	Execute string
 }
 
 // ProcessTasks is the main taskopen workflow
-func (tp *TaskProcessor) ProcessTasks(ctx context.Context, filters []string, single bool, interactive bool) error {
-	// Skip context for now and use provided filters directly
-	allFilters := filters
-	if len(allFilters) == 0 && tp.config.General.BaseFilter != "" {
-		// Add base filter when no filters provided
-		allFilters = append(allFilters, strings.Fields(tp.config.General.BaseFilter)...)
+func (tp *TaskProcessor) ProcessTasks(ctx context.Context, opts ProcessOptions) error {
+	if opts.Mode == "" {
+		opts.Mode = ModeNormal
 	}
 
-	// Get tasks from taskwarrior
-	tasks, err := tp.getTasksFromTaskwarrior(ctx, allFilters)
+	actionables, tasks, err := tp.CollectActionables(ctx, opts)
 	if err != nil {
-		return errors.Wrap(err, errors.ActionExecution, "Failed to get tasks from taskwarrior")
+		return err
 	}
 
 	if len(tasks) == 0 {
@@ -58,50 +101,195 @@ func (tp *TaskProcessor) ProcessTasks(ctx context.Context, filters []string, sin
 		return nil
 	}
 
+	if len(actionables) == 0 {
+		return tp.handleNoActionables(ctx, tasks, opts)
+	}
+
+	// In each mode the hook also covers the tasks without actionables when
+	// other tasks have some. A failing hook does not stop the actions.
+	var hookErr error
+	if tp.config.General.NoAnnotationMode == config.NoAnnotationEach {
+		if remaining := tasksWithoutActionables(tasks, actionables); len(remaining) > 0 {
+			hookErr = tp.handleNoActionables(ctx, remaining, opts)
+		}
+	}
+
+	if err := tp.runActionables(ctx, opts, actionables); err != nil {
+		return err
+	}
+	return hookErr
+}
+
+// runActionables executes, lists or offers the found actionables
+func (tp *TaskProcessor) runActionables(ctx context.Context, opts ProcessOptions, actionables []*Actionable) error {
+	if tp.config.General.GroupDuplicates {
+		actionables = tp.groupDuplicates(actionables)
+	}
+
+	// Execute actions
+	if opts.Mode == ModeBatch {
+		return tp.executeBulk(ctx, actionables)
+	}
+
+	if len(actionables) == 1 {
+		return tp.executeActionable(ctx, actionables[0])
+	}
+
+	// Inline output is only needed when actionables are displayed
+	tp.resolveInlineOutputs(ctx, actionables)
+
+	if opts.Interactive {
+		return tp.interactiveSelection(ctx, actionables)
+	}
+	return tp.listActionables(actionables)
+}
+
+// CollectActionables queries taskwarrior and returns the sorted actionables
+// together with the tasks that matched the filters
+func (tp *TaskProcessor) CollectActionables(ctx context.Context, opts ProcessOptions) ([]*Actionable, []map[string]any, error) {
+	if opts.Mode == "" {
+		opts.Mode = ModeNormal
+	}
+
+	sortKeys, err := parseSortKeys(tp.config.General.Sort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var contextFilter string
+	if !opts.NoContext {
+		contextFilter = tp.activeContextFilter(ctx)
+	}
+
+	// Get tasks from taskwarrior
+	tasks, err := tp.getTasksFromTaskwarrior(ctx, combineFilters(contextFilter, tp.config.General.BaseFilter, opts.Filters))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(tasks) == 0 {
+		return nil, tasks, nil
+	}
+
 	tp.logger.Debug("Retrieved tasks", map[string]any{"count": len(tasks)})
 
 	// Find actionable items
-	actionables, err := tp.findActionableItems(ctx, tasks, single)
+	single := (opts.Single && opts.Mode != ModeAny) || opts.Execute != ""
+	actionables, err := tp.findActionableItems(ctx, tasks, tp.selectActions(opts), single)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	if len(actionables) == 0 {
-		if tp.config.General.NoAnnotationHook != "" && len(tasks) == 1 {
-			tp.formatter.Warning("No actionable items found")
-			taskEnv := tp.buildEnvironment(tasks[0])
-			result, err := tp.executor.Execute(ctx, "sh", []string{"-c", tp.config.General.NoAnnotationHook}, &exec.ExecutionOptions{Environment: taskEnv})
-			if err != nil {
-				tp.logger.Error("Failed executing no_annotation_hook", map[string]any{"command": tp.config.General.NoAnnotationHook, "error": err.Error()})
-				return errors.Wrap(err, errors.ActionExecution, "Failed to execute no_annotation_hook")
-			}
-
-			if result.ExitCode != 0 {
-				tp.logger.Error("no_annotation_hook exited with non-zero code", map[string]any{"command": tp.config.General.NoAnnotationHook, "exit_code": result.ExitCode})
-				return errors.New(errors.ActionExecution, fmt.Sprintf("no_annotation_hook failed with exit code %d", result.ExitCode))
-			}
-			return nil
-		}
-		return nil
+	if opts.Execute != "" {
+		overrideCommand(actionables, opts.Execute)
 	}
 
 	// Sort actionables
-	tp.sortActionables(actionables)
+	tp.sortActionables(actionables, sortKeys)
 
-	// Execute actions
-	if interactive && len(actionables) > 1 {
-		return tp.interactiveSelection(ctx, actionables)
-	} else if len(actionables) == 1 {
-		return tp.executeActionable(ctx, actionables[0])
-	} else {
-		return tp.listActionables(actionables)
+	return actionables, tasks, nil
+}
+
+// activeContextFilter returns the read filter of the active Taskwarrior
+// context, or an empty string when no context applies
+func (tp *TaskProcessor) activeContextFilter(ctx context.Context) string {
+	taskContext, err := tp.client.ActiveContext(ctx)
+	if err != nil {
+		tp.logger.Debug("Could not determine Taskwarrior context", map[string]any{"error": err.Error()})
+		return ""
+	}
+	if taskContext == nil {
+		return ""
+	}
+	if taskContext.Filter == "" {
+		tp.logger.Warn("Active context has no filter defined", map[string]any{"context": taskContext.Name})
+		return ""
+	}
+
+	tp.logger.Debug("Applying Taskwarrior context", map[string]any{
+		"context": taskContext.Name,
+		"filter":  taskContext.Filter,
+	})
+	return taskContext.Filter
+}
+
+// combineFilters builds the taskwarrior filter arguments. The base filter is
+// only used when no filters were given. The context filter and the remaining
+// filters are parenthesized so that "or" in either cannot escape the other.
+func combineFilters(contextFilter, baseFilter string, filters []string) []string {
+	if len(filters) == 0 && baseFilter != "" {
+		filters = strings.Fields(baseFilter)
+	}
+	if contextFilter == "" {
+		return filters
+	}
+
+	combined := []string{"(", contextFilter, ")"}
+	if len(filters) > 0 {
+		combined = append(combined, "(")
+		combined = append(combined, filters...)
+		combined = append(combined, ")")
 	}
+	return combined
 }
 
-// executeFilter runs a filter command and returns whether it passed
-func (tp *TaskProcessor) executeFilter(ctx context.Context, command string, env map[string]string) bool {
-	expandedCommand := tp.expandEnvironmentVars(command, env)
-	result, err := tp.executor.Execute(ctx, "sh", []string{"-c", expandedCommand},
-		&exec.ExecutionOptions{Environment: env})
-	return err == nil && result.ExitCode == 0
+// overrideCommand replaces the configured command of each actionable while
+// keeping the environment of its match. Fallbacks are dropped since they
+// would run configured commands instead.
+func overrideCommand(actionables []*Actionable, command string) {
+	for _, actionable := range actionables {
+		actionable.Action.Command = command
+		actionable.overridden = true
+		actionable.Action.Fallback = nil
+		actionable.alternatives = nil
+	}
+}
+
+// executeBulk runs several actionables without prompting, one after another
+// or concurrently depending on the bulk_execution setting, and prints a
+// summary of the results
+func (tp *TaskProcessor) executeBulk(ctx context.Context, actionables []*Actionable) error {
+	results := make([]error, len(actionables))
+
+	if tp.config.General.BulkExecution == config.BulkExecutionConcurrent {
+		var wg sync.WaitGroup
+		for i, actionable := range actionables {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = tp.executeActionable(ctx, actionable)
+			}()
+		}
+		wg.Wait()
+	} else {
+		for i, actionable := range actionables {
+			results[i] = tp.executeActionable(ctx, actionable)
+		}
+	}
+
+	tp.formatter.Subheader("Summary")
+
+	failed := 0
+	for i, err := range results {
+		actionable := actionables[i]
+		if err == nil {
+			tp.formatter.Success("%s: %s", actionable.Action.Name, actionable.Text)
+			continue
+		}
+
+		failed++
+		tp.formatter.Error("%s: %s", actionable.Action.Name, actionable.Text)
+		tp.logger.Error("Bulk action failed", map[string]any{
+			"action": actionable.Action.Name,
+			"text":   actionable.Text,
+			"error":  err.Error(),
+		})
+	}
+
+	if failed > 0 {
+		return errors.New(errors.ActionExecution, fmt.Sprintf("%d of %d actions failed", failed, len(actionables)))
+	}
+
+	tp.formatter.Info("%d actions completed successfully", len(actionables))
+	return nil
 }
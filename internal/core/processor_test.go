@@ -0,0 +1,207 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/config"
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func TestCombineFilters(t *testing.T) {
+	tests := []struct {
+		name          string
+		contextFilter string
+		baseFilter    string
+		filters       []string
+		want          []string
+	}{
+		{
+			name:    "filters only",
+			filters: []string{"+bug"},
+			want:    []string{"+bug"},
+		},
+		{
+			name:       "base filter when no filters",
+			baseFilter: "+PENDING -WAITING",
+			want:       []string{"+PENDING", "-WAITING"},
+		},
+		{
+			name:       "filters replace base filter",
+			baseFilter: "+PENDING",
+			filters:    []string{"project:home"},
+			want:       []string{"project:home"},
+		},
+		{
+			name:          "context alone",
+			contextFilter: "project:work or +urgent",
+			want:          []string{"(", "project:work or +urgent", ")"},
+		},
+		{
+			name:          "context with base filter",
+			contextFilter: "project:work",
+			baseFilter:    "+PENDING",
+			want:          []string{"(", "project:work", ")", "(", "+PENDING", ")"},
+		},
+		{
+			name:          "context with filters",
+			contextFilter: "project:work",
+			filters:       []string{"+bug", "or", "+feature"},
+			want:          []string{"(", "project:work", ")", "(", "+bug", "or", "+feature", ")"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := combineFilters(tt.contextFilter, tt.baseFilter, tt.filters)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("combineFilters() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteBulk(t *testing.T) {
+	for _, strategy := range []string{config.BulkExecutionSequential, config.BulkExecutionConcurrent} {
+		t.Run(strategy, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.General.BulkExecution = strategy
+			tp := NewTaskProcessor(cfg)
+
+			actionables := []*Actionable{
+				{Text: "one", Action: types.Action{Name: "ok", Command: "true"}},
+				{Text: "two", Action: types.Action{Name: "fail", Command: "false"}},
+				{Text: "three", Action: types.Action{Name: "ok", Command: "true"}},
+			}
+
+			err := tp.executeBulk(context.Background(), actionables)
+			if err == nil || !strings.Contains(err.Error(), "1 of 3 actions failed") {
+				t.Errorf("executeBulk() error = %v, want 1 of 3 actions failed", err)
+			}
+		})
+	}
+}
+
+func TestSortActionables_Ties(t *testing.T) {
+	tp := newTestProcessor(nil)
+
+	newActionable := func(text string, urgency float64, priority int) *Actionable {
+		return &Actionable{
+			Text:   text,
+			Task:   map[string]any{"urgency": urgency},
+			Action: types.Action{Name: text, Priority: priority},
+		}
+	}
+
+	tests := []struct {
+		name string
+		sort string
+		want []string
+	}{
+		{"ties keep discovery order", "urgency-", []string{"high", "a2", "a1", "b"}},
+		{"action priority", "action.priority-", []string{"a1", "a2", "high", "b"}},
+		{"priority then urgency", "action.priority-,urgency-", []string{"a1", "high", "a2", "b"}},
+		{"no keys", "", []string{"a2", "a1", "high", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortKeys, err := parseSortKeys(tt.sort)
+			if err != nil {
+				t.Fatalf("parseSortKeys(%q) error = %v", tt.sort, err)
+			}
+			actionables := []*Actionable{
+				newActionable("a2", 1, 0),
+				newActionable("a1", 1, 5),
+				newActionable("high", 9, 0),
+				newActionable("b", 0, 0),
+			}
+
+			tp.sortActionables(actionables, sortKeys)
+
+			var got []string
+			for _, actionable := range actionables {
+				got = append(got, actionable.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sorted = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectActionables_Client(t *testing.T) {
+	useEmptyTaskrc(t)
+	taskBin := filepath.Join(t.TempDir(), "task")
+	script := `#!/bin/sh
+case "$*" in
+*rc.gc=off*+PENDING*export*) ;;
+*) echo "unexpected arguments: $*" >&2; exit 2 ;;
+esac
+cat <<'JSON'
+[{"id":1,"uuid":"a","description":"Review","entry":"20240101T090000Z",
+  "annotations":[{"entry":"20240102T100000Z","description":"~/spec.pdf"}]}]
+JSON
+`
+	if err := os.WriteFile(taskBin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.General.TaskBin = taskBin
+	cfg.Actions = []types.Action{{Name: "pdf", Target: "annotations", Regex: `\.pdf$`, Command: "true"}}
+	tp := NewTaskProcessor(cfg)
+
+	actionables, tasks, err := tp.CollectActionables(context.Background(), ProcessOptions{})
+	if err != nil {
+		t.Fatalf("CollectActionables() error = %v", err)
+	}
+	if len(tasks) != 1 || len(actionables) != 1 {
+		t.Fatalf("got %d tasks and %d actionables, want 1 of each", len(tasks), len(actionables))
+	}
+	if got := actionables[0]; got.Environment["UUID"] != "a" || got.Entry != "20240102T100000Z" {
+		t.Errorf("actionable has UUID %q and entry %q, want a and 20240102T100000Z", got.Environment["UUID"], got.Entry)
+	}
+}
+
+func TestOverrideCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	tp := newTestProcessor([]types.Action{
+		{Name: "pdf", Target: "annotations", Regex: `\.pdf$`, Command: "false", Fallback: []string{"any"}},
+		{Name: "any", Target: "annotations", Regex: `.*`, Command: "false"},
+	})
+
+	tasks := []map[string]any{
+		{"uuid": "a", "annotations": []any{
+			map[string]any{"description": "doc: ~/a b.pdf"},
+			map[string]any{"description": "x; touch pwned"},
+		}},
+	}
+	actionables, err := tp.findActionableItems(context.Background(), tasks, tp.actions, true)
+	if err != nil {
+		t.Fatalf("findActionableItems() error = %v", err)
+	}
+
+	overrideCommand(actionables, `printf '%s|%s|%s\n' "$UUID" $LABEL $ANNOTATION >> `+out)
+	for _, actionable := range actionables {
+		if len(actionable.Action.Fallback) != 0 || len(actionable.alternatives) != 0 {
+			t.Errorf("%s keeps its fallbacks", actionable.Text)
+		}
+		if err := tp.executeActionable(context.Background(), actionable); err != nil {
+			t.Fatalf("executeActionable(%s) error = %v", actionable.Text, err)
+		}
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "a|doc|doc: ~/a b.pdf\na||x; touch pwned\n"
+	if string(got) != want {
+		t.Errorf("overridden commands wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/taskwarrior"
+)
+
+// dependsTarget is the task attribute listing the UUIDs a task depends on
+const dependsTarget = "depends"
+
+// targetValue resolves an action target in task. A target is either a task
+// attribute, including UDAs, or a dotted path into nested values where
+// numeric segments index arrays, e.g. "links.0.href".
+func targetValue(task map[string]any, target string) (any, bool) {
+	if value, ok := task[target]; ok {
+		return value, true
+	}
+
+	var current any = task
+	for segment := range strings.SplitSeq(target, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// udaTypes returns the types of the UDAs declared in taskrc by name. A
+// taskrc that cannot be read leaves values formatted without type.
+func (tp *TaskProcessor) udaTypes() map[string]string {
+	if tp.udas != nil {
+		return tp.udas
+	}
+
+	tp.udas = make(map[string]string)
+	rc, err := tp.client.Taskrc()
+	if err != nil {
+		tp.logger.Debug("Could not read UDA types from taskrc", map[string]any{"error": err.Error()})
+		return tp.udas
+	}
+	for _, uda := range rc.UDAs() {
+		tp.udas[uda.Name] = uda.Type
+	}
+	return tp.udas
+}
+
+// targetElements returns the texts that actions are matched against. Arrays
+// such as tags and depends yield one text per element. Taskwarrior before
+// 2.6 exports depends as a comma-separated string, which is split as well.
+// UdaType is the type declared in taskrc when target is a UDA, and selects
+// how values are formatted.
+func targetElements(target string, value any, udaType string) []string {
+	switch v := value.(type) {
+	case []any:
+		elements := make([]string, 0, len(v))
+		for _, element := range v {
+			elements = append(elements, udaText(udaType, element))
+		}
+		return elements
+	case string:
+		if target == dependsTarget || strings.HasSuffix(target, "."+dependsTarget) {
+			return strings.Split(v, ",")
+		}
+	}
+	return []string{udaText(udaType, value)}
+}
+
+// udaText formats a UDA value by its declared type. Dates are printed in
+// Taskwarrior's export format and durations in ISO 8601, also when the
+// export holds them as epoch or plain seconds. Values that do not fit the
+// type, and attributes without a type, are formatted by attributeText.
+func udaText(udaType string, value any) string {
+	switch udaType {
+	case "date":
+		switch v := value.(type) {
+		case string:
+			if ts, err := taskwarrior.ParseTimestamp(v); err == nil {
+				return ts.String()
+			}
+			if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return taskwarrior.Timestamp{Time: time.Unix(seconds, 0)}.String()
+			}
+		case float64:
+			return taskwarrior.Timestamp{Time: time.Unix(int64(v), 0)}.String()
+		}
+	case "duration":
+		switch v := value.(type) {
+		case string:
+			if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return isoDuration(seconds)
+			}
+		case float64:
+			return isoDuration(int64(v))
+		}
+	case "numeric":
+		if v, ok := value.(string); ok {
+			if number, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return strconv.FormatFloat(number, 'f', -1, 64)
+			}
+		}
+	}
+	return attributeText(value)
+}
+
+// isoDuration formats seconds as an ISO 8601 duration such as P1DT2H
+func isoDuration(seconds int64) string {
+	var b strings.Builder
+	if seconds < 0 {
+		b.WriteByte('-')
+		seconds = -seconds
+	}
+	b.WriteByte('P')
+	if days := seconds / 86400; days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if seconds%86400 == 0 && seconds > 0 {
+		return b.String()
+	}
+
+	b.WriteByte('T')
+	hours, minutes, rest := seconds%86400/3600, seconds%3600/60, seconds%60
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+	}
+	if rest > 0 || (hours == 0 && minutes == 0) {
+		fmt.Fprintf(&b, "%dS", rest)
+	}
+	return b.String()
+}
+
+// attributeText formats a single exported value. Numbers are printed
+// without exponent or trailing zeros, and nested values as JSON.
+func attributeText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]any, []any:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/types"
+)
+
+func TestTargetValue(t *testing.T) {
+	task := map[string]any{
+		"url":   "https://example.com",
+		"links": []any{map[string]any{"href": "https://a.example"}},
+		"meta":  map[string]any{"owner": "alice"},
+	}
+
+	tests := []struct {
+		target string
+		want   any
+		found  bool
+	}{
+		{"url", "https://example.com", true},
+		{"links.0.href", "https://a.example", true},
+		{"meta.owner", "alice", true},
+		{"links.1.href", nil, false},
+		{"missing", nil, false},
+		{"url.host", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, found := targetValue(task, tt.target)
+		if found != tt.found || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("targetValue(%q) = %v, %v, want %v, %v", tt.target, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestTargetElements(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		value   any
+		udaType string
+		want    []string
+	}{
+		{"scalar string", "url", "https://example.com", "", []string{"https://example.com"}},
+		{"array", "tags", []any{"work", "home"}, "", []string{"work", "home"}},
+		{"legacy depends string", "depends", "uuid-a,uuid-b", "", []string{"uuid-a", "uuid-b"}},
+		{"numeric uda", "estimate", float64(1.5), "", []string{"1.5"}},
+		{"large number", "size", float64(1e21), "", []string{"1000000000000000000000"}},
+		{"nested value", "meta", map[string]any{"a": "b"}, "", []string{`{"a":"b"}`}},
+		{"date uda", "review", "2024-03-01T17:00:00Z", "date", []string{"20240301T170000Z"}},
+		{"epoch date uda", "review", "1709312400", "date", []string{"20240301T170000Z"}},
+		{"iso duration uda", "estimate", "PT2H", "duration", []string{"PT2H"}},
+		{"seconds duration uda", "estimate", "93784", "duration", []string{"P1DT2H3M4S"}},
+		{"whole day duration uda", "estimate", float64(172800), "duration", []string{"P2D"}},
+		{"numeric uda as string", "size", "2.50", "numeric", []string{"2.5"}},
+		{"string list uda", "urls", []any{"https://a.example", "https://b.example"}, "string", []string{"https://a.example", "https://b.example"}},
+		{"value not fitting type", "review", "someday", "date", []string{"someday"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := targetElements(tt.target, tt.value, tt.udaType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("targetElements(%q, %v) = %q, want %q", tt.target, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindActionableItems_Targets(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "tag", Target: "tags", Regex: `^work$`, Command: "echo $FILE"},
+		{Name: "dep", Target: "depends", Regex: `.+`, Command: "task $DEPENDS_UUID info"},
+		{Name: "link", Target: "links.0.href", Regex: `^https://`, Command: "open $FILE"},
+	})
+
+	tasks := []map[string]any{{
+		"uuid":    "task-uuid",
+		"tags":    []any{"home", "work"},
+		"depends": []any{"dep-a", "dep-b"},
+		"links":   []any{map[string]any{"href": "https://a.example"}},
+	}}
+
+	actionables, err := tp.findActionableItems(context.Background(), tasks, tp.actions, true)
+	if err != nil {
+		t.Fatalf("findActionableItems() error = %v", err)
+	}
+
+	var got []string
+	for _, actionable := range actionables {
+		got = append(got, actionable.Action.Name+":"+actionable.Text)
+		if actionable.Action.Name == "dep" && actionable.Environment["DEPENDS_UUID"] != actionable.Text {
+			t.Errorf("DEPENDS_UUID = %q, want %q", actionable.Environment["DEPENDS_UUID"], actionable.Text)
+		}
+	}
+
+	want := []string{"tag:work", "dep:dep-a", "dep:dep-b", "link:https://a.example"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("actionables = %q, want %q", got, want)
+	}
+}
+
+func TestFindActionableItems_UDATypes(t *testing.T) {
+	tp := newTestProcessor([]types.Action{
+		{Name: "review", Target: "review", Regex: `^2024`, Command: "echo $FILE"},
+	})
+	tp.udas = map[string]string{"review": "date"}
+
+	tasks := []map[string]any{{"uuid": "task-uuid", "review": "2024-03-01T17:00:00Z"}}
+	actionables, err := tp.findActionableItems(context.Background(), tasks, tp.actions, true)
+	if err != nil {
+		t.Fatalf("findActionableItems() error = %v", err)
+	}
+	if len(actionables) != 1 || actionables[0].Text != "20240301T170000Z" {
+		t.Fatalf("actionables = %v, want one for 20240301T170000Z", actionables)
+	}
+}
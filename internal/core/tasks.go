@@ -2,56 +2,18 @@ package core
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strconv"
-	"time"
-
-	"github.com/johnconnor-sec/taskopen-go/internal/exec"
 )
 
-// getTasksFromTaskwarrior retrieves tasks as JSON from taskwarrior
+// getTasksFromTaskwarrior exports the tasks matching filters with all of
+// their attributes, including UDAs. Errors are typed Taskwarrior errors from
+// the client.
 func (tp *TaskProcessor) getTasksFromTaskwarrior(ctx context.Context, filters []string) ([]map[string]any, error) {
-	// Build taskwarrior export command: task [general_args] [filters] export
-	args := append([]string{}, tp.config.General.TaskArgs...) // Copy slice
-	args = append(args, filters...)
-	args = append(args, "export")
-
-	result, err := tp.executor.Execute(ctx, tp.config.General.TaskBin, args,
-		&exec.ExecutionOptions{
-			CaptureOutput: true,
-			Timeout:       10 * time.Second,
-		})
+	tasks, err := tp.client.ExportAttributes(ctx, filters)
 	if err != nil {
-		return nil, fmt.Errorf("taskwarrior execution failed: %w", err)
-	}
-
-	if result == nil {
-		return nil, fmt.Errorf("no result from taskwarrior execution")
-	}
-
-	if result.ExitCode != 0 {
-		tp.logger.Error("Taskwarrior export failed", map[string]any{
-			"exit_code": result.ExitCode,
-			"stderr":    result.Stderr,
-			"stdout":    result.Stdout,
-		})
-		return nil, fmt.Errorf("taskwarrior export failed with exit code %d: %s", result.ExitCode, result.Stderr)
-	}
-
-	if result.Stdout == "" {
-		// No tasks found - return empty slice instead of error
-		return []map[string]any{}, nil
-	}
-
-	// Parse JSON output
-	var tasks []map[string]any
-	if err := json.Unmarshal([]byte(result.Stdout), &tasks); err != nil {
-		tp.logger.Error("Failed to parse taskwarrior JSON", map[string]any{
-			"error":  err.Error(),
-			"stdout": result.Stdout,
-		})
-		return nil, fmt.Errorf("failed to parse taskwarrior JSON: %w", err)
+		tp.logger.Error("Taskwarrior export failed", map[string]any{"error": err.Error()})
+		return nil, err
 	}
 
 	tp.logger.Info("Retrieved tasks from taskwarrior", map[string]any{
@@ -82,17 +44,3 @@ func (tp *TaskProcessor) getTaskInt(task map[string]any, key string) int {
 	}
 	return 0
 }
-
-func (tp *TaskProcessor) getTaskFloat(task map[string]any, key string) float64 {
-	if value, exists := task[key]; exists {
-		if floatVal, ok := value.(float64); ok {
-			return floatVal
-		}
-		if strVal, ok := value.(string); ok {
-			if floatVal, err := strconv.ParseFloat(strVal, 64); err == nil {
-				return floatVal
-			}
-		}
-	}
-	return 0.0
-}
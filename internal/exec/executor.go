@@ -75,6 +75,9 @@ type RetryOptions struct {
 
 	// Retry on these exit codes
 	RetryOnExitCodes []int
+
+	// RetryIf, when set, decides whether a failed attempt is retried
+	RetryIf func(result *ExecutionResult, err error) bool
 }
 
 // ExecutionResult holds the result of process execution.
@@ -182,6 +185,9 @@ func (e *Executor) Execute(ctx context.Context, command string, args []string, o
 		if len(options.Retry.RetryOnExitCodes) > 0 {
 			finalOptions.Retry.RetryOnExitCodes = options.Retry.RetryOnExitCodes
 		}
+		if options.Retry.RetryIf != nil {
+			finalOptions.Retry.RetryIf = options.Retry.RetryIf
+		}
 	}
 
 	return e.executeWithRetry(ctx, command, args, finalOptions)
@@ -291,10 +297,12 @@ func (e *Executor) ExecuteDirect(ctx context.Context, command string, options *E
 		return nil, errors.New(errors.ActionExecution, "Empty command")
 	}
 
-	executable := cmdParts[0]
-	args := cmdParts[1:]
+	return e.ExecuteDirectArgs(ctx, cmdParts[0], cmdParts[1:], options)
+}
 
-	// Use direct execution
+// ExecuteDirectArgs executes a program with already split arguments without
+// a shell wrapper
+func (e *Executor) ExecuteDirectArgs(ctx context.Context, executable string, args []string, options *ExecutionOptions) (*ExecutionResult, error) {
 	finalOptions := e.defaultOptions
 	if options != nil {
 		finalOptions = e.mergeOptions(finalOptions, *options)
@@ -344,6 +352,9 @@ func (e *Executor) executeWithRetry(ctx context.Context, command string, args []
 				break
 			}
 		}
+		if options.Retry.RetryIf != nil && !options.Retry.RetryIf(result, err) {
+			break
+		}
 	}
 
 	// Return the last result and error
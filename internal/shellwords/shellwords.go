@@ -0,0 +1,69 @@
+// Package shellwords splits command lines into words the way sh does.
+package shellwords
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Split splits a command line into words following sh quoting rules,
+// without performing any expansion.
+func Split(command string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	inWord := false
+	quote := byte(0)
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+
+		case c == '\\' && i+1 < len(command):
+			next := command[i+1]
+			if quote == '"' && !strings.ContainsRune("$`\"\\\n", rune(next)) {
+				current.WriteByte(c)
+			}
+			current.WriteByte(next)
+			inWord = true
+			i++
+
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+
+		case c == '"' || c == '\'':
+			quote = c
+			inWord = true
+
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+
+		default:
+			current.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed quote in command: %s", command)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+
+	return words, nil
+}
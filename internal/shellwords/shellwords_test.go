@@ -0,0 +1,35 @@
+package shellwords
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{"plain", "xdg-open  file.pdf", []string{"xdg-open", "file.pdf"}, false},
+		{"single quotes are literal", `echo 'a\b "c"'`, []string{"echo", `a\b "c"`}, false},
+		{"double quotes", `echo "a \"b\" \x"`, []string{"echo", `a "b" \x`}, false},
+		{"quoted injection", `echo 'x'\''; rm'`, []string{"echo", "x'; rm"}, false},
+		{"empty word", "echo ''", []string{"echo", ""}, false},
+		{"joined parts", `~/notes/'abc''.md'`, []string{"~/notes/abc.md"}, false},
+		{"unclosed quote", `echo "abc`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Split(%q) error = %v, wantErr %v", tt.command, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
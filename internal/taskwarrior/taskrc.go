@@ -0,0 +1,313 @@
+package taskwarrior
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+)
+
+// defaultDataLocation is used when neither taskrc nor TASKDATA set one.
+const defaultDataLocation = "~/.task"
+
+// systemRcDirs are searched for relative includes that are not next to the
+// including file, such as the themes and holiday files Taskwarrior installs.
+var systemRcDirs = []string{
+	"/usr/share/taskwarrior",
+	"/usr/local/share/taskwarrior",
+	"/usr/share/doc/task/rc",
+	"/usr/local/share/doc/task/rc",
+}
+
+// Taskrc holds the merged settings of a Taskwarrior configuration file, its
+// includes, the TASKDATA variable and rc. overrides.
+type Taskrc struct {
+	// Path is the main configuration file
+	Path string
+
+	// Files lists every file read, in the order they were read
+	Files []string
+
+	// Overrides holds the rc. settings given on the command line
+	Overrides map[string]string
+
+	// Warnings describes relative includes that were skipped because the
+	// file was not found
+	Warnings []string
+
+	settings map[string]string
+}
+
+// UDA describes a user defined attribute declared in taskrc.
+type UDA struct {
+	Name   string
+	Type   string
+	Label  string
+	Values []string
+}
+
+// TaskrcPath returns the configuration file Taskwarrior reads for args: an
+// rc: argument, then TASKRC, then ~/.taskrc, then the XDG location.
+func TaskrcPath(args []string) string {
+	for _, arg := range slices.Backward(args) {
+		if path, ok := strings.CutPrefix(arg, "rc:"); ok {
+			return expandHome(path)
+		}
+	}
+
+	if path := os.Getenv("TASKRC"); path != "" {
+		return expandHome(path)
+	}
+
+	home := expandHome("~/.taskrc")
+	if fileExists(home) {
+		return home
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = expandHome("~/.config")
+	}
+	if xdg := filepath.Join(configHome, "task", "taskrc"); fileExists(xdg) {
+		return xdg
+	}
+	return home
+}
+
+// LoadTaskrc reads the configuration Taskwarrior uses when called with args.
+// Include directives are followed, TASKDATA overrides data.location, and
+// rc.name=value or rc.name:value arguments override any setting.
+func LoadTaskrc(args []string) (*Taskrc, error) {
+	rc := &Taskrc{
+		Path:      TaskrcPath(args),
+		Overrides: make(map[string]string),
+		settings:  make(map[string]string),
+	}
+
+	if err := rc.readFile(rc.Path, nil); err != nil {
+		return nil, err
+	}
+
+	if dataDir := os.Getenv("TASKDATA"); dataDir != "" {
+		rc.settings["data.location"] = dataDir
+	}
+
+	for _, arg := range args {
+		name, value, ok := parseOverride(arg)
+		if !ok {
+			continue
+		}
+		rc.Overrides[name] = value
+		rc.settings[name] = value
+	}
+
+	return rc, nil
+}
+
+// readFile merges the settings of path, following includes. Stack holds the
+// files currently being read to detect include cycles.
+func (rc *Taskrc) readFile(path string, stack []string) error {
+	if slices.Contains(stack, path) {
+		return errors.New(errors.ConfigInvalid, "Taskrc include cycle").
+			WithDetails(strings.Join(append(stack, path), " -> "))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		taskErr := errors.Wrap(err, errors.FileNotFound, "Could not read taskrc").
+			WithDetails(fmt.Sprintf("File: %s", path))
+		if len(stack) == 0 {
+			taskErr.WithSuggestion("Set TASKRC or pass rc:<path> in taskargs to point at your taskrc")
+		} else {
+			taskErr.WithSuggestion(fmt.Sprintf("Check the include directive in %s", stack[len(stack)-1]))
+		}
+		return taskErr
+	}
+	defer file.Close()
+
+	rc.Files = append(rc.Files, path)
+	stack = append(stack, path)
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if include, ok := strings.CutPrefix(line, "include "); ok {
+			includePath, found := resolveInclude(strings.TrimSpace(include), filepath.Dir(path))
+			if !found {
+				rc.Warnings = append(rc.Warnings, fmt.Sprintf("%s:%d: skipped include %s, file not found", path, lineNumber, strings.TrimSpace(include)))
+				continue
+			}
+			if err := rc.readFile(includePath, stack); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return errors.New(errors.ConfigInvalid, "Invalid taskrc line").
+				WithDetails(fmt.Sprintf("%s:%d: %s", path, lineNumber, line)).
+				WithSuggestion("Settings must have the form name=value")
+		}
+		rc.settings[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, errors.ConfigInvalid, "Could not read taskrc").
+			WithDetails(fmt.Sprintf("File: %s", path))
+	}
+	return nil
+}
+
+// resolveInclude returns the file an include directive names. Absolute
+// paths are used as they are. Relative paths are looked up next to the
+// including file in dir, then in systemRcDirs; found is false when neither
+// has the file.
+func resolveInclude(include, dir string) (path string, found bool) {
+	include = expandHome(include)
+	if filepath.IsAbs(include) {
+		return include, true
+	}
+
+	for _, base := range append([]string{dir}, systemRcDirs...) {
+		if path := filepath.Join(base, include); fileExists(path) {
+			return path, true
+		}
+	}
+	return filepath.Join(dir, include), false
+}
+
+// parseOverride splits an rc.name=value or rc.name:value argument
+func parseOverride(arg string) (string, string, bool) {
+	setting, ok := strings.CutPrefix(arg, "rc.")
+	if !ok {
+		return "", "", false
+	}
+
+	i := strings.IndexAny(setting, "=:")
+	if i <= 0 {
+		return "", "", false
+	}
+	return setting[:i], setting[i+1:], true
+}
+
+// Get returns a setting, or an empty string when it is not set.
+func (rc *Taskrc) Get(name string) string {
+	return rc.settings[name]
+}
+
+// Lookup returns a setting and whether it is set.
+func (rc *Taskrc) Lookup(name string) (string, bool) {
+	value, ok := rc.settings[name]
+	return value, ok
+}
+
+// DataLocation returns the expanded directory holding the task data.
+func (rc *Taskrc) DataLocation() string {
+	if location := rc.settings["data.location"]; location != "" {
+		return expandHome(location)
+	}
+	return expandHome(defaultDataLocation)
+}
+
+// UDAs returns the user defined attributes declared with uda.<name>.type,
+// sorted by name.
+func (rc *Taskrc) UDAs() []UDA {
+	var udas []UDA
+	for key, value := range rc.settings {
+		name, ok := strings.CutPrefix(key, "uda.")
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutSuffix(name, ".type")
+		if !ok || strings.Contains(name, ".") {
+			continue
+		}
+
+		uda := UDA{Name: name, Type: value, Label: rc.settings["uda."+name+".label"]}
+		if values := rc.settings["uda."+name+".values"]; values != "" {
+			uda.Values = strings.Split(values, ",")
+		}
+		udas = append(udas, uda)
+	}
+
+	slices.SortFunc(udas, func(a, b UDA) int { return strings.Compare(a.Name, b.Name) })
+	return udas
+}
+
+// Contexts returns the defined contexts with their read filters, sorted by
+// name.
+func (rc *Taskrc) Contexts() []Context {
+	var names []string
+	for key := range rc.settings {
+		name, ok := strings.CutPrefix(key, "context.")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSuffix(strings.TrimSuffix(name, ".read"), ".write")
+		if !strings.Contains(name, ".") && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	contexts := make([]Context, 0, len(names))
+	for _, name := range names {
+		filter, _ := contextReadFilter(name, func(setting string) (string, error) {
+			return rc.settings[setting], nil
+		})
+		contexts = append(contexts, Context{Name: name, Filter: filter})
+	}
+	return contexts
+}
+
+// contextReadFilter returns the read filter of context name, looking up
+// taskrc settings such as context.work.read with get. Taskwarrior 2.6
+// introduced separate read and write filters; older versions store the
+// filter under the context name itself.
+func contextReadFilter(name string, get func(setting string) (string, error)) (string, error) {
+	filter, err := get("context." + name + ".read")
+	if err != nil || filter != "" {
+		return filter, err
+	}
+	return get("context." + name)
+}
+
+// ActiveContext returns the name of the context selected in taskrc, or an
+// empty string when none is active.
+func (rc *Taskrc) ActiveContext() string {
+	if name := rc.settings["context"]; name != "none" {
+		return name
+	}
+	return ""
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,155 @@
+package taskwarrior
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name inside dir and returns its path
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadTaskrc(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TASKDATA", "")
+	writeFile(t, dir, "themes/dark.theme", "color.active=bold\n")
+	writeFile(t, dir, "udas.rc", `
+uda.estimate.type=duration
+uda.estimate.label=Estimate
+uda.size.type=string
+uda.size.values=S,M,L
+`)
+	path := writeFile(t, dir, "taskrc", `# main taskrc
+data.location=~/tasks   # trailing comment
+include themes/dark.theme
+include `+filepath.Join(dir, "udas.rc")+`
+context=work
+context.work.read=+work
+context.work.write=+work
+context.home=+home
+gc=on
+`)
+	t.Setenv("TASKRC", path)
+
+	rc, err := LoadTaskrc([]string{"rc.gc=off", "rc.context:home", "+bug"})
+	if err != nil {
+		t.Fatalf("LoadTaskrc() error = %v", err)
+	}
+
+	if len(rc.Files) != 3 {
+		t.Errorf("Files = %v, want taskrc and two includes", rc.Files)
+	}
+	if got := rc.Get("color.active"); got != "bold" {
+		t.Errorf("included setting = %q, want bold", got)
+	}
+	if got := rc.Get("gc"); got != "off" {
+		t.Errorf("gc = %q, want the rc. override off", got)
+	}
+	if got := rc.ActiveContext(); got != "home" {
+		t.Errorf("ActiveContext() = %q, want home", got)
+	}
+	if !strings.HasSuffix(rc.DataLocation(), "/tasks") {
+		t.Errorf("DataLocation() = %q, want the expanded ~/tasks", rc.DataLocation())
+	}
+
+	wantUDAs := []UDA{
+		{Name: "estimate", Type: "duration", Label: "Estimate"},
+		{Name: "size", Type: "string", Values: []string{"S", "M", "L"}},
+	}
+	if got := rc.UDAs(); !reflect.DeepEqual(got, wantUDAs) {
+		t.Errorf("UDAs() = %+v, want %+v", got, wantUDAs)
+	}
+
+	wantContexts := []Context{{Name: "home", Filter: "+home"}, {Name: "work", Filter: "+work"}}
+	if got := rc.Contexts(); !reflect.DeepEqual(got, wantContexts) {
+		t.Errorf("Contexts() = %+v, want %+v", got, wantContexts)
+	}
+
+	t.Setenv("TASKDATA", "/srv/tasks")
+	rc, err = LoadTaskrc(nil)
+	if err != nil {
+		t.Fatalf("LoadTaskrc() error = %v", err)
+	}
+	if got := rc.DataLocation(); got != "/srv/tasks" {
+		t.Errorf("DataLocation() = %q, want TASKDATA", got)
+	}
+}
+
+func TestLoadTaskrc_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.rc", "include b.rc\n")
+	writeFile(t, dir, "b.rc", "include a.rc\n")
+	writeFile(t, dir, "bad.rc", "just words\n")
+	writeFile(t, dir, "missing.rc", "include "+filepath.Join(dir, "nowhere.rc")+"\n")
+
+	tests := []struct {
+		file    string
+		message string
+	}{
+		{"a.rc", "Taskrc include cycle"},
+		{"bad.rc", "Invalid taskrc line"},
+		{"missing.rc", "Could not read taskrc"},
+		{"absent.rc", "Could not read taskrc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			_, err := LoadTaskrc([]string{"rc:" + filepath.Join(dir, tt.file)})
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("LoadTaskrc() error = %v, want %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestLoadTaskrc_Includes(t *testing.T) {
+	dir := t.TempDir()
+	system := filepath.Join(dir, "system")
+	writeFile(t, system, "dark-256.theme", "color.active=bold\n")
+	path := writeFile(t, dir, "home/.taskrc", "include dark-256.theme\ninclude holidays.de-DE.rc\ngc=off\n")
+
+	saved := systemRcDirs
+	systemRcDirs = []string{filepath.Join(dir, "absent"), system}
+	t.Cleanup(func() { systemRcDirs = saved })
+
+	rc, err := LoadTaskrc([]string{"rc:" + path})
+	if err != nil {
+		t.Fatalf("LoadTaskrc() error = %v", err)
+	}
+
+	if want := []string{path, filepath.Join(system, "dark-256.theme")}; !reflect.DeepEqual(rc.Files, want) {
+		t.Errorf("Files = %v, want %v", rc.Files, want)
+	}
+	if got := rc.Get("color.active"); got != "bold" {
+		t.Errorf("setting from system theme = %q, want bold", got)
+	}
+	if got := rc.Get("gc"); got != "off" {
+		t.Errorf("setting after the skipped include = %q, want off", got)
+	}
+	if len(rc.Warnings) != 1 || !strings.Contains(rc.Warnings[0], "holidays.de-DE.rc") {
+		t.Errorf("Warnings = %q, want one for holidays.de-DE.rc", rc.Warnings)
+	}
+}
+
+func TestTaskrcPath(t *testing.T) {
+	t.Setenv("TASKRC", "/etc/taskrc")
+
+	if got := TaskrcPath([]string{"rc:/tmp/a", "rc:/tmp/b"}); got != "/tmp/b" {
+		t.Errorf("TaskrcPath() = %q, want the last rc: argument", got)
+	}
+	if got := TaskrcPath([]string{"rc.gc=off"}); got != "/etc/taskrc" {
+		t.Errorf("TaskrcPath() = %q, want TASKRC", got)
+	}
+}
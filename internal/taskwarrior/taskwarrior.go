@@ -4,7 +4,12 @@ package taskwarrior
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	osexec "os/exec"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,7 +24,8 @@ var DefaultArgs = []string{
 	"rc.gc=off",
 }
 
-// Task represents a Taskwarrior task.
+// Task represents a Taskwarrior task. Dates that ParseTimestamp rejects
+// leave their typed field unset; Attributes still holds them as exported.
 type Task struct {
 	ID          int          `json:"id,omitempty"`
 	UUID        string       `json:"uuid"`
@@ -30,17 +36,51 @@ type Task struct {
 	Tags        []string     `json:"tags,omitempty"`
 	Annotations []Annotation `json:"annotations,omitempty"`
 	Urgency     float64      `json:"urgency,omitempty"`
-	Due         *time.Time   `json:"due,omitempty"`
-	Created     *time.Time   `json:"entry,omitempty"`
-	Modified    *time.Time   `json:"modified,omitempty"`
+	Due         *Timestamp   `json:"due,omitempty"`
+	Scheduled   *Timestamp   `json:"scheduled,omitempty"`
+	Wait        *Timestamp   `json:"wait,omitempty"`
+	Start       *Timestamp   `json:"start,omitempty"`
+	End         *Timestamp   `json:"end,omitempty"`
+	Created     *Timestamp   `json:"entry,omitempty"`
+	Modified    *Timestamp   `json:"modified,omitempty"`
+
+	// Attributes holds every exported attribute, including UDAs, as
+	// decoded by encoding/json
+	Attributes map[string]any `json:"-"`
 
 	// Raw JSON for additional fields
 	Raw json.RawMessage `json:"-"`
 }
 
+// timestampAttributes are the exported attributes decoded into the
+// Timestamp fields of Task
+var timestampAttributes = []string{"due", "scheduled", "wait", "start", "end", "entry", "modified"}
+
+// coreAttributes are the attributes Taskwarrior defines itself. Any other
+// exported attribute is a UDA.
+var coreAttributes = map[string]bool{
+	"id": true, "uuid": true, "description": true, "status": true,
+	"project": true, "priority": true, "tags": true, "annotations": true,
+	"urgency": true, "due": true, "scheduled": true, "wait": true,
+	"start": true, "end": true, "entry": true, "modified": true,
+	"until": true, "recur": true, "mask": true, "imask": true,
+	"parent": true, "depends": true,
+}
+
+// UDAs returns the user defined attributes of the task.
+func (t *Task) UDAs() map[string]any {
+	udas := make(map[string]any)
+	for name, value := range t.Attributes {
+		if !coreAttributes[name] {
+			udas[name] = value
+		}
+	}
+	return udas
+}
+
 // Annotation represents a task annotation.
 type Annotation struct {
-	Entry       time.Time `json:"entry"`
+	Entry       Timestamp `json:"entry"`
 	Description string    `json:"description"`
 }
 
@@ -55,7 +95,9 @@ type Client struct {
 	executor   *exec.Executor
 	taskBinary string
 	taskArgs   []string
-	timeout    time.Duration
+	// userArgs are the configured arguments, without DefaultArgs
+	userArgs []string
+	timeout  time.Duration
 }
 
 // NewClient creates a new Taskwarrior client.
@@ -68,12 +110,15 @@ func NewClient(taskBinary string, taskArgs []string, timeout time.Duration) *Cli
 	execOptions := exec.ExecutionOptions{
 		Timeout:       timeout,
 		CaptureOutput: true,
+		// Queries are retried only when Taskwarrior timed out or reported a
+		// lock held by another process; other failures will not go away.
+		// Writes override this with a single attempt.
 		Retry: exec.RetryOptions{
 			MaxAttempts:       3,
 			BaseDelay:         100 * time.Millisecond,
 			MaxDelay:          2 * time.Second,
 			BackoffMultiplier: 2.0,
-			RetryOnExitCodes:  []int{}, // Don't retry on specific exit codes for now
+			RetryIf:           retryableQuery,
 		},
 		Sandbox: exec.SandboxOptions{
 			// Taskwarrior is generally safe, but we can add restrictions if needed
@@ -84,18 +129,17 @@ func NewClient(taskBinary string, taskArgs []string, timeout time.Duration) *Cli
 	return &Client{
 		executor:   exec.New(execOptions),
 		taskBinary: taskBinary,
-		taskArgs:   append(DefaultArgs, taskArgs...),
+		taskArgs:   slices.Concat(DefaultArgs, taskArgs),
+		userArgs:   slices.Clone(taskArgs),
 		timeout:    timeout,
 	}
 }
 
 // Version returns the Taskwarrior version.
 func (c *Client) Version(ctx context.Context) (string, error) {
-	args := append(c.taskArgs, "_version")
-
-	result, err := c.executor.Execute(ctx, c.taskBinary, args, nil)
+	result, err := c.run(ctx, "_version")
 	if err != nil {
-		return "", errors.TaskwarriorNotFoundError()
+		return "", err
 	}
 
 	if result.ExitCode != 0 {
@@ -111,14 +155,78 @@ func (c *Client) Version(ctx context.Context) (string, error) {
 	return strings.TrimSpace(result.Stdout), nil
 }
 
+// Get returns the value of a DOM reference such as "rc.context", or an
+// empty string when it is not set.
+func (c *Client) Get(ctx context.Context, reference string) (string, error) {
+	result, err := c.run(ctx, "_get", reference)
+	if err != nil {
+		return "", err
+	}
+
+	if result.ExitCode != 0 {
+		return "", errors.New(errors.TaskwarriorQuery, fmt.Sprintf("Failed to get %s", reference)).
+			WithDetails(fmt.Sprintf("Exit code: %d, stderr: %s", result.ExitCode, result.Stderr))
+	}
+
+	return strings.TrimSpace(result.Stdout), nil
+}
+
+// Helper runs a helper command such as _projects, _tags or _ids and returns
+// the non-empty lines of its output.
+func (c *Client) Helper(ctx context.Context, command string) ([]string, error) {
+	result, err := c.run(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ExitCode != 0 {
+		return nil, errors.New(errors.TaskwarriorQuery, fmt.Sprintf("Failed to run %s", command)).
+			WithDetails(fmt.Sprintf("Exit code: %d, stderr: %s", result.ExitCode, result.Stderr))
+	}
+
+	var lines []string
+	for line := range strings.Lines(result.Stdout) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// ActiveContext returns the context selected in taskrc together with its
+// read filter, or nil when no context is active. The parsed taskrc is used
+// when it can be read; otherwise Taskwarrior is asked with _get.
+func (c *Client) ActiveContext(ctx context.Context) (*Context, error) {
+	if rc, err := c.Taskrc(); err == nil {
+		name := rc.ActiveContext()
+		if name == "" {
+			return nil, nil
+		}
+		filter, _ := contextReadFilter(name, func(setting string) (string, error) {
+			return rc.Get(setting), nil
+		})
+		return &Context{Name: name, Filter: filter}, nil
+	}
+
+	name, err := c.Get(ctx, "rc.context")
+	if err != nil || name == "" || name == "none" {
+		return nil, err
+	}
+
+	filter, err := contextReadFilter(name, func(setting string) (string, error) {
+		return c.Get(ctx, "rc."+setting)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Context{Name: name, Filter: filter}, nil
+}
+
 // Export retrieves tasks in JSON format using streaming for large datasets.
 func (c *Client) Export(ctx context.Context, filters []string) ([]Task, error) {
-	args := append(c.taskArgs, filters...)
-	args = append(args, "export")
-
-	result, err := c.executor.Execute(ctx, c.taskBinary, args, nil)
+	result, err := c.run(ctx, append(slices.Clone(filters), "export")...)
 	if err != nil {
-		return nil, errors.Wrap(err, errors.TaskwarriorQuery, "Failed to export tasks")
+		return nil, err
 	}
 
 	if result.ExitCode != 0 {
@@ -134,6 +242,68 @@ func (c *Client) Export(ctx context.Context, filters []string) ([]Task, error) {
 	return c.parseTasksJSON(result.Stdout)
 }
 
+// Taskrc loads the Taskwarrior configuration the client's commands use,
+// including the rc. overrides among the configured arguments. DefaultArgs
+// only shape command output and are left out.
+func (c *Client) Taskrc() (*Taskrc, error) {
+	return LoadTaskrc(c.userArgs)
+}
+
+// ExportAttributes retrieves tasks as maps holding every exported attribute,
+// including UDAs, in the order Taskwarrior returns them.
+func (c *Client) ExportAttributes(ctx context.Context, filters []string) ([]map[string]any, error) {
+	tasks, err := c.Export(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make([]map[string]any, len(tasks))
+	for i, task := range tasks {
+		attributes[i] = task.Attributes
+	}
+	return attributes, nil
+}
+
+// retryableQuery reports whether a failed query may succeed when run again
+func retryableQuery(result *exec.ExecutionResult, _ error) bool {
+	if result == nil {
+		return false
+	}
+	return result.TimedOut || strings.Contains(strings.ToLower(result.Stderr), "lock")
+}
+
+// run executes taskwarrior with the client's arguments followed by args.
+// Failures to run it at all are reported as typed Taskwarrior errors; the
+// exit code is left to the caller.
+func (c *Client) run(ctx context.Context, args ...string) (*exec.ExecutionResult, error) {
+	return c.runWithOptions(ctx, nil, args)
+}
+
+// runWithOptions is run with execution options overriding the client's
+func (c *Client) runWithOptions(ctx context.Context, options *exec.ExecutionOptions, args []string) (*exec.ExecutionResult, error) {
+	fullArgs := slices.Concat(c.taskArgs, args)
+
+	result, err := c.executor.Execute(ctx, c.taskBinary, fullArgs, options)
+	if err == nil {
+		return result, nil
+	}
+
+	if result != nil && result.TimedOut {
+		return nil, errors.Wrap(err, errors.TaskwarriorTimeout, "Taskwarrior did not respond in time").
+			WithDetails(fmt.Sprintf("Timeout: %v, command: %s %s", c.timeout, c.taskBinary, strings.Join(fullArgs, " "))).
+			WithSuggestions([]string{
+				"Check whether another Taskwarrior process holds a lock",
+				"Run the command manually to see whether it waits for input",
+			})
+	}
+	if stderrors.Is(err, osexec.ErrNotFound) || stderrors.Is(err, fs.ErrNotExist) {
+		return nil, errors.TaskwarriorNotFoundError().
+			WithDetails(fmt.Sprintf("Could not run %q", c.taskBinary))
+	}
+	return nil, errors.Wrap(err, errors.TaskwarriorQuery, "Failed to run Taskwarrior").
+		WithDetails(fmt.Sprintf("Command: %s %s", c.taskBinary, strings.Join(fullArgs, " ")))
+}
+
 // Query executes a Taskwarrior query and returns matching tasks.
 func (c *Client) Query(ctx context.Context, filters []string) ([]Task, error) {
 	// Add status:pending by default if no status filter provided
@@ -174,10 +344,21 @@ func (c *Client) parseTasksJSON(jsonData string) ([]Task, error) {
 	tasks := make([]Task, len(rawTasks))
 	for i, rawTask := range rawTasks {
 		var task Task
-		if err := json.Unmarshal(rawTask, &task); err != nil {
-			return nil, errors.Wrap(err, errors.TaskwarriorQuery, "Failed to parse task").
+		if err := json.Unmarshal(rawTask, &task.Attributes); err != nil {
+			return nil, errors.Wrap(err, errors.TaskwarriorQuery, "Failed to parse task attributes").
 				WithDetails(fmt.Sprintf("Task %d in JSON array", i+1))
 		}
+		if err := json.Unmarshal(rawTask, &task); err != nil {
+			// A date in an unknown format should not fail the whole export:
+			// decode again without it, leaving the typed field unset while
+			// Attributes keeps the value as exported
+			task = Task{Attributes: task.Attributes}
+			valid, _ := json.Marshal(withoutInvalidDates(task.Attributes))
+			if err := json.Unmarshal(valid, &task); err != nil {
+				return nil, errors.Wrap(err, errors.TaskwarriorQuery, "Failed to parse task").
+					WithDetails(fmt.Sprintf("Task %d in JSON array, UUID %v", i+1, task.Attributes["uuid"]))
+			}
+		}
 
 		// Store raw JSON for access to additional fields
 		task.Raw = rawTask
@@ -187,10 +368,37 @@ func (c *Client) parseTasksJSON(jsonData string) ([]Task, error) {
 	return tasks, nil
 }
 
-// min returns the minimum of two integers.
-func min(a, b int) int {
-	if a < b {
-		return a
+// withoutInvalidDates returns a copy of exported task attributes without the
+// dates, including annotation entries, that ParseTimestamp rejects
+func withoutInvalidDates(attributes map[string]any) map[string]any {
+	valid := maps.Clone(attributes)
+	for _, name := range timestampAttributes {
+		if value, ok := valid[name]; ok && !isTimestamp(value) {
+			delete(valid, name)
+		}
+	}
+
+	if annotations, ok := valid["annotations"].([]any); ok {
+		cleaned := make([]any, len(annotations))
+		for i, element := range annotations {
+			if annotation, ok := element.(map[string]any); ok && !isTimestamp(annotation["entry"]) {
+				annotation = maps.Clone(annotation)
+				delete(annotation, "entry")
+				element = annotation
+			}
+			cleaned[i] = element
+		}
+		valid["annotations"] = cleaned
+	}
+	return valid
+}
+
+// isTimestamp reports whether value is a date ParseTimestamp accepts
+func isTimestamp(value any) bool {
+	text, ok := value.(string)
+	if !ok {
+		return false
 	}
-	return b
+	_, err := ParseTimestamp(text)
+	return err == nil
 }
@@ -0,0 +1,213 @@
+package taskwarrior
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+)
+
+// writeFakeTask writes a shell script standing in for the task binary and
+// returns its path
+func writeFakeTask(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "task")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClient_Export(t *testing.T) {
+	taskBin := writeFakeTask(t, `cat <<'JSON'
+[{"id":1,"uuid":"a","description":"Review","status":"pending","entry":"20240101T090000Z",
+  "due":"20240301T170000Z","urgency":8.5,"estimate":"PT2H",
+  "annotations":[{"entry":"20240102T100000Z","description":"~/spec.pdf"}]}]
+JSON
+`)
+
+	client := NewClient(taskBin, nil, time.Second)
+	tasks, err := client.Export(context.Background(), []string{"+work"})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("Export() returned %d tasks, want 1", len(tasks))
+	}
+
+	task := tasks[0]
+	if task.Due == nil || !task.Due.Equal(time.Date(2024, 3, 1, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("Due = %v, want 2024-03-01 17:00 UTC", task.Due)
+	}
+	if task.Created == nil || task.Created.String() != "20240101T090000Z" {
+		t.Errorf("Created = %v, want 20240101T090000Z", task.Created)
+	}
+	if got := task.Annotations[0].Entry.String(); got != "20240102T100000Z" {
+		t.Errorf("annotation entry = %s, want 20240102T100000Z", got)
+	}
+	if task.Attributes["urgency"] != 8.5 || task.Attributes["due"] != "20240301T170000Z" {
+		t.Errorf("Attributes = %v, want raw urgency and due", task.Attributes)
+	}
+	if udas := task.UDAs(); len(udas) != 1 || udas["estimate"] != "PT2H" {
+		t.Errorf("UDAs() = %v, want only estimate", udas)
+	}
+}
+
+func TestClient_ExportInvalidDates(t *testing.T) {
+	taskBin := writeFakeTask(t, `cat <<'JSON'
+[{"id":1,"uuid":"a","description":"Broken","due":"someday","entry":"20240101T090000Z",
+  "annotations":[{"entry":"yesterday-ish","description":"~/spec.pdf"}]},
+ {"id":2,"uuid":"b","description":"Fine","due":"20240301T170000Z"}]
+JSON
+`)
+
+	tasks, err := NewClient(taskBin, nil, time.Second).Export(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Export() returned %d tasks, want 2", len(tasks))
+	}
+
+	broken := tasks[0]
+	if broken.Due != nil || broken.Attributes["due"] != "someday" {
+		t.Errorf("Due = %v and attribute %v, want unset and someday", broken.Due, broken.Attributes["due"])
+	}
+	if broken.Created == nil || broken.Created.String() != "20240101T090000Z" {
+		t.Errorf("Created = %v, want 20240101T090000Z", broken.Created)
+	}
+	if len(broken.Annotations) != 1 || !broken.Annotations[0].Entry.IsZero() || broken.Annotations[0].Description != "~/spec.pdf" {
+		t.Errorf("Annotations = %+v, want ~/spec.pdf without entry", broken.Annotations)
+	}
+	if tasks[1].Due == nil || tasks[1].Due.String() != "20240301T170000Z" {
+		t.Errorf("second task Due = %v, want 20240301T170000Z", tasks[1].Due)
+	}
+}
+
+func TestClient_QueryRetries(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		timeout time.Duration
+		want    int
+	}{
+		{"bad filter", "echo 'bad filter' >&2\nexit 2\n", time.Second, 1},
+		{"lock held", "echo 'Could not lock the data files' >&2\nexit 1\n", time.Second, 3},
+		{"timeout", "exec sleep 5\n", 100 * time.Millisecond, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := filepath.Join(t.TempDir(), "runs")
+			taskBin := writeFakeTask(t, "echo run >> "+runs+"\n"+tt.script)
+
+			if _, err := NewClient(taskBin, nil, tt.timeout).Export(context.Background(), nil); err == nil {
+				t.Fatal("Export() succeeded, want error")
+			}
+
+			data, err := os.ReadFile(runs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := strings.Count(string(data), "run"); n != tt.want {
+				t.Errorf("task ran %d times, want %d", n, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		taskBin string
+		timeout time.Duration
+		want    errors.ErrorType
+	}{
+		{"export fails", writeFakeTask(t, "echo 'bad filter' >&2\nexit 2\n"), time.Second, errors.TaskwarriorQuery},
+		{"invalid JSON", writeFakeTask(t, "echo '[{'\n"), time.Second, errors.TaskwarriorQuery},
+		{"missing binary", filepath.Join(t.TempDir(), "missing-task"), time.Second, errors.TaskwarriorNotFound},
+		{"timeout", writeFakeTask(t, "exec sleep 5\n"), 100 * time.Millisecond, errors.TaskwarriorTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(tt.taskBin, nil, tt.timeout)
+			_, err := client.Export(context.Background(), nil)
+
+			var taskErr *errors.TaskopenError
+			if !stderrors.As(err, &taskErr) {
+				t.Fatalf("Export() error = %v, want *errors.TaskopenError", err)
+			}
+			if taskErr.Type != tt.want {
+				t.Errorf("error type = %s, want %s", taskErr.Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Helper(t *testing.T) {
+	taskBin := writeFakeTask(t, `for arg; do last=$arg; done
+[ "$last" = _projects ] || exit 1
+printf 'home\n\nwork.report\n'
+`)
+
+	got, err := NewClient(taskBin, nil, time.Second).Helper(context.Background(), "_projects")
+	if err != nil {
+		t.Fatalf("Helper() error = %v", err)
+	}
+	if want := []string{"home", "work.report"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Helper() = %q, want %q", got, want)
+	}
+}
+
+func TestClient_ActiveContext(t *testing.T) {
+	dir := t.TempDir()
+	taskrc := writeFile(t, dir, "taskrc", "context=work\ncontext.work.read=+work\ncontext.work.write=+work\n")
+
+	// The parsed taskrc answers without running Taskwarrior
+	unused := writeFakeTask(t, "exit 3\n")
+	got, err := NewClient(unused, []string{"rc:" + taskrc}, time.Second).ActiveContext(context.Background())
+	if err != nil {
+		t.Fatalf("ActiveContext() error = %v", err)
+	}
+	if want := (&Context{Name: "work", Filter: "+work"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ActiveContext() = %+v, want %+v", got, want)
+	}
+
+	// Without a readable taskrc, Taskwarrior is asked
+	taskBin := writeFakeTask(t, `for arg; do last=$arg; done
+case "$last" in
+rc.context) echo home ;;
+rc.context.home) echo +home ;;
+esac
+`)
+	got, err = NewClient(taskBin, []string{"rc:" + filepath.Join(dir, "missing")}, time.Second).ActiveContext(context.Background())
+	if err != nil {
+		t.Fatalf("ActiveContext() error = %v", err)
+	}
+	if want := (&Context{Name: "home", Filter: "+home"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ActiveContext() = %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_TaskrcOverrides(t *testing.T) {
+	taskrc := writeFile(t, t.TempDir(), "taskrc", "gc=on\n")
+
+	rc, err := NewClient("task", []string{"rc:" + taskrc, "rc.context=work"}, time.Second).Taskrc()
+	if err != nil {
+		t.Fatalf("Taskrc() error = %v", err)
+	}
+	// DefaultArgs are not the user's overrides
+	if want := map[string]string{"context": "work"}; !reflect.DeepEqual(rc.Overrides, want) {
+		t.Errorf("Overrides = %v, want %v", rc.Overrides, want)
+	}
+	if got := rc.Get("gc"); got != "on" {
+		t.Errorf("Get(gc) = %q, want the taskrc value", got)
+	}
+}
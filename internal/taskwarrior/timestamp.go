@@ -0,0 +1,51 @@
+package taskwarrior
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TimestampLayout is the format of dates in Taskwarrior's JSON export.
+const TimestampLayout = "20060102T150405Z"
+
+// Timestamp is a date as exported by Taskwarrior, e.g. 20240101T120000Z.
+// RFC 3339 dates are accepted as well when decoding.
+type Timestamp struct {
+	time.Time
+}
+
+// ParseTimestamp parses a Taskwarrior date in export or RFC 3339 format.
+func ParseTimestamp(value string) (Timestamp, error) {
+	for _, layout := range []string{TimestampLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{t.UTC()}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("invalid Taskwarrior timestamp %q", value)
+}
+
+// String formats the timestamp the way Taskwarrior exports it.
+func (t Timestamp) String() string {
+	return t.UTC().Format(TimestampLayout)
+}
+
+// MarshalJSON encodes the timestamp in Taskwarrior's export format.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a Taskwarrior date string.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("timestamp must be a string: %w", err)
+	}
+
+	parsed, err := ParseTimestamp(value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
@@ -0,0 +1,41 @@
+package taskwarrior
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestamp_JSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{`"20240101T093000Z"`, time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC), false},
+		{`"2024-01-01T10:30:00+01:00"`, time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC), false},
+		{`"2024-01-01"`, time.Time{}, true},
+		{`20240101`, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var ts Timestamp
+			err := json.Unmarshal([]byte(tt.input), &ts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !ts.Equal(tt.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.input, ts.Time, tt.want)
+			}
+		})
+	}
+
+	data, err := json.Marshal(Timestamp{time.Date(2024, 1, 1, 10, 30, 0, 0, time.FixedZone("CET", 3600))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"20240101T093000Z"` {
+		t.Errorf("Marshal() = %s, want \"20240101T093000Z\"", data)
+	}
+}
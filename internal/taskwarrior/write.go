@@ -0,0 +1,111 @@
+package taskwarrior
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+	"github.com/johnconnor-sec/taskopen-go/internal/exec"
+)
+
+// uuidRegex matches a task UUID. Write operations only accept UUIDs since
+// IDs change whenever the working set is rebuilt.
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// createdRegex extracts the UUID from the output of add with rc.verbose=new-uuid
+var createdRegex = regexp.MustCompile(`Created task ([0-9a-fA-F-]{36})`)
+
+// writeArgs keep write commands from prompting for confirmation
+var writeArgs = []string{"rc.confirmation=off", "rc.recurrence.confirmation=off"}
+
+// Annotate adds an annotation to the task with the given UUID.
+func (c *Client) Annotate(ctx context.Context, uuid, text string) error {
+	// "--" stops taskwarrior from parsing the text as modifications
+	_, err := c.write(ctx, "annotate", uuid, "--", text)
+	return err
+}
+
+// Denotate removes the annotation matching text from the task.
+func (c *Client) Denotate(ctx context.Context, uuid, text string) error {
+	_, err := c.write(ctx, "denotate", uuid, "--", text)
+	return err
+}
+
+// Modify applies modifications such as "project:home" or "+next" to the
+// task. Each modification is passed as a single argument.
+func (c *Client) Modify(ctx context.Context, uuid string, modifications ...string) error {
+	if len(modifications) == 0 {
+		return errors.New(errors.ValidationFailed, "No modifications given").
+			WithDetails(fmt.Sprintf("Task: %s", uuid))
+	}
+	_, err := c.write(ctx, "modify", uuid, modifications...)
+	return err
+}
+
+// Start marks the task as started.
+func (c *Client) Start(ctx context.Context, uuid string) error {
+	_, err := c.write(ctx, "start", uuid)
+	return err
+}
+
+// Stop marks the task as no longer started.
+func (c *Client) Stop(ctx context.Context, uuid string) error {
+	_, err := c.write(ctx, "stop", uuid)
+	return err
+}
+
+// Done marks the task as completed.
+func (c *Client) Done(ctx context.Context, uuid string) error {
+	_, err := c.write(ctx, "done", uuid)
+	return err
+}
+
+// Add creates a task with description and modifications such as
+// "project:home" and returns its UUID. The description is taken literally.
+func (c *Client) Add(ctx context.Context, description string, modifications ...string) (string, error) {
+	if strings.TrimSpace(description) == "" {
+		return "", errors.New(errors.ValidationFailed, "Task description is empty")
+	}
+
+	args := slices.Concat([]string{"rc.verbose=new-uuid", "add"}, modifications, []string{"--", description})
+	result, err := c.runWrite(ctx, "add", args)
+	if err != nil {
+		return "", err
+	}
+
+	match := createdRegex.FindStringSubmatch(result.Stdout)
+	if match == nil {
+		return "", errors.New(errors.TaskwarriorQuery, "Could not determine the UUID of the new task").
+			WithDetails(strings.TrimSpace(result.Stdout))
+	}
+	return match[1], nil
+}
+
+// write runs command on the task with the given UUID
+func (c *Client) write(ctx context.Context, command, uuid string, args ...string) (*exec.ExecutionResult, error) {
+	if !uuidRegex.MatchString(uuid) {
+		return nil, errors.New(errors.ValidationFailed, fmt.Sprintf("Invalid task UUID: %q", uuid)).
+			WithSuggestion("Write operations take task UUIDs, not IDs or filters")
+	}
+	return c.runWrite(ctx, command, slices.Concat([]string{uuid, command}, args))
+}
+
+// runWrite runs a command that changes tasks. It is not retried, since a
+// failed attempt may have changed some data, and a non-zero exit code is
+// reported as an error.
+func (c *Client) runWrite(ctx context.Context, command string, args []string) (*exec.ExecutionResult, error) {
+	result, err := c.runWithOptions(ctx, &exec.ExecutionOptions{Retry: exec.RetryOptions{MaxAttempts: 1}},
+		slices.Concat(writeArgs, args))
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ExitCode != 0 {
+		return nil, errors.New(errors.TaskwarriorQuery, fmt.Sprintf("Taskwarrior %s failed with exit code %d", command, result.ExitCode)).
+			WithDetails(strings.TrimSpace(result.Stderr + "\n" + result.Stdout))
+	}
+	return result, nil
+}
@@ -0,0 +1,185 @@
+package taskwarrior
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/errors"
+)
+
+const testUUID = "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+
+// newRecordingClient returns a client whose task binary records its
+// arguments, one per line, and then runs script
+func newRecordingClient(t *testing.T, script string) (*Client, func() []string) {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "args")
+	taskBin := writeFakeTask(t, `for arg in "$@"; do printf '%s\n' "$arg" >> `+log+`; done
+`+script)
+
+	recorded := func() []string {
+		data, err := os.ReadFile(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var args []string
+		for _, arg := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			// Leave out the client's rc. overrides
+			if !strings.HasPrefix(arg, "rc.") {
+				args = append(args, arg)
+			}
+		}
+		return args
+	}
+	return NewClient(taskBin, nil, time.Second), recorded
+}
+
+func TestClient_WriteOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(*Client) error
+		want []string
+	}{
+		{
+			name: "annotate",
+			run: func(c *Client) error {
+				return c.Annotate(context.Background(), testUUID, "notes: ~/a b.md; rm -rf $HOME project:x")
+			},
+			want: []string{testUUID, "annotate", "--", "notes: ~/a b.md; rm -rf $HOME project:x"},
+		},
+		{
+			name: "denotate",
+			run:  func(c *Client) error { return c.Denotate(context.Background(), testUUID, "-- old link") },
+			want: []string{testUUID, "denotate", "--", "-- old link"},
+		},
+		{
+			name: "modify",
+			run: func(c *Client) error {
+				return c.Modify(context.Background(), testUUID, "project:home", "+next", "description:read the spec")
+			},
+			want: []string{testUUID, "modify", "project:home", "+next", "description:read the spec"},
+		},
+		{
+			name: "start",
+			run:  func(c *Client) error { return c.Start(context.Background(), testUUID) },
+			want: []string{testUUID, "start"},
+		},
+		{
+			name: "stop",
+			run:  func(c *Client) error { return c.Stop(context.Background(), testUUID) },
+			want: []string{testUUID, "stop"},
+		},
+		{
+			name: "done",
+			run:  func(c *Client) error { return c.Done(context.Background(), testUUID) },
+			want: []string{testUUID, "done"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, recorded := newRecordingClient(t, "")
+			if err := tt.run(client); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got := recorded(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("arguments = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Add(t *testing.T) {
+	client, recorded := newRecordingClient(t, "echo 'Created task "+testUUID+".'\n")
+
+	uuid, err := client.Add(context.Background(), "Call +bob about project:x", "project:home", "due:tomorrow")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if uuid != testUUID {
+		t.Errorf("Add() = %q, want %q", uuid, testUUID)
+	}
+
+	want := []string{"add", "project:home", "due:tomorrow", "--", "Call +bob about project:x"}
+	if got := recorded(); !reflect.DeepEqual(got, want) {
+		t.Errorf("arguments = %q, want %q", got, want)
+	}
+}
+
+func TestClient_WriteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		run     func(*Client) error
+		want    errors.ErrorType
+		message string
+	}{
+		{
+			name:    "taskwarrior failure",
+			script:  "echo 'Task 0a1b2c3d already started.' >&2\nexit 1\n",
+			run:     func(c *Client) error { return c.Start(context.Background(), testUUID) },
+			want:    errors.TaskwarriorQuery,
+			message: "Taskwarrior start failed with exit code 1",
+		},
+		{
+			name:    "ID instead of UUID",
+			run:     func(c *Client) error { return c.Done(context.Background(), "42") },
+			want:    errors.ValidationFailed,
+			message: `Invalid task UUID: "42"`,
+		},
+		{
+			name:    "filter instead of UUID",
+			run:     func(c *Client) error { return c.Annotate(context.Background(), "+work", "x") },
+			want:    errors.ValidationFailed,
+			message: "Invalid task UUID",
+		},
+		{
+			name:    "no modifications",
+			run:     func(c *Client) error { return c.Modify(context.Background(), testUUID) },
+			want:    errors.ValidationFailed,
+			message: "No modifications given",
+		},
+		{
+			name:   "add without UUID in output",
+			script: "echo 'Created task 7.'\n",
+			run: func(c *Client) error {
+				_, err := c.Add(context.Background(), "Something")
+				return err
+			},
+			want:    errors.TaskwarriorQuery,
+			message: "Could not determine the UUID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newRecordingClient(t, tt.script)
+			err := tt.run(client)
+
+			var taskErr *errors.TaskopenError
+			if !stderrors.As(err, &taskErr) {
+				t.Fatalf("error = %v, want *errors.TaskopenError", err)
+			}
+			if taskErr.Type != tt.want || !strings.Contains(taskErr.Message, tt.message) {
+				t.Errorf("error = %s %q, want %s containing %q", taskErr.Type, taskErr.Message, tt.want, tt.message)
+			}
+		})
+	}
+}
+
+func TestClient_WriteNotRetried(t *testing.T) {
+	// A lock error is retried for queries but not for writes
+	client, recorded := newRecordingClient(t, "echo 'Could not lock the data files' >&2\nexit 1\n")
+	if err := client.Done(context.Background(), testUUID); err == nil {
+		t.Fatal("Done() succeeded, want error")
+	}
+	if got := recorded(); len(got) != 2 {
+		t.Errorf("task ran with %q, want a single attempt", got)
+	}
+}
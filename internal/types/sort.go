@@ -0,0 +1,69 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SortKey is a general.sort field and its direction.
+type SortKey struct {
+	Key  string
+	Desc bool
+}
+
+// ActionableSortKeys sort by properties of the actionable rather than by
+// task attributes.
+var ActionableSortKeys = []string{"annot", "annotation-entry", "action", "action.priority", "label"}
+
+// attributeKeyRegex matches the names of task attributes, including UDAs
+var attributeKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// sortKeyNearMisses maps likely misspellings of ActionableSortKeys to the
+// key that was probably meant. They are rejected rather than sorted as task
+// attributes, which would silently do nothing.
+var sortKeyNearMisses = map[string]string{
+	"annotation":       "annot",
+	"annotations":      "annot",
+	"annotation_entry": "annotation-entry",
+	"annot-entry":      "annotation-entry",
+	"actions":          "action",
+	"action-priority":  "action.priority",
+	"action_priority":  "action.priority",
+	"labels":           "label",
+}
+
+// ParseSortKeys parses a comma-separated sort order such as "urgency-,annot".
+// Besides ActionableSortKeys, any task attribute or UDA name is accepted,
+// except for near misses of the actionable keys such as "annotation".
+func ParseSortKeys(sort string) ([]SortKey, error) {
+	var keys []SortKey
+
+	for field := range strings.SplitSeq(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key := field
+		desc := false
+		if strings.HasSuffix(key, "-") {
+			desc = true
+			key = key[:len(key)-1]
+		} else if strings.HasSuffix(key, "+") {
+			key = key[:len(key)-1]
+		}
+
+		if meant, ok := sortKeyNearMisses[key]; ok {
+			return nil, fmt.Errorf("unknown sort key %q, did you mean %q?", field, meant)
+		}
+		if !slices.Contains(ActionableSortKeys, key) && !attributeKeyRegex.MatchString(key) {
+			return nil, fmt.Errorf("unknown sort key %q", field)
+		}
+
+		keys = append(keys, SortKey{Key: key, Desc: desc})
+	}
+
+	return keys, nil
+}
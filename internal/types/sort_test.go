@@ -0,0 +1,37 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	tests := []struct {
+		sort    string
+		want    []SortKey
+		wantErr bool
+	}{
+		{"urgency-,annot", []SortKey{{"urgency", true}, {"annot", false}}, false},
+		{" due+ , estimate- ,", []SortKey{{"due", false}, {"estimate", true}}, false},
+		{"action,label,annotation-entry-,action.priority-", []SortKey{{"action", false}, {"label", false}, {"annotation-entry", true}, {"action.priority", true}}, false},
+		{"", nil, false},
+		{"actionable,actiondate-,annotator", []SortKey{{"actionable", false}, {"actiondate", true}, {"annotator", false}}, false},
+		{"action.name", nil, true},
+		{"labels", nil, true},
+		{"annotation", nil, true},
+		{"urgency*", nil, true},
+		{"-", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			got, err := ParseSortKeys(tt.sort)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSortKeys(%q) error = %v, wantErr %v", tt.sort, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSortKeys(%q) = %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+}
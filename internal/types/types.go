@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/when"
 )
 
 // Action represents a taskopen action configuration.
@@ -19,6 +21,9 @@ type Action struct {
 	Modes         []string `json:"modes" yaml:"modes"`
 	FilterCommand string   `json:"filtercommand" yaml:"filtercommand"`
 	InlineCommand string   `json:"inlinecommand" yaml:"inlinecommand"`
+	Priority      int      `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Fallback      []string `json:"fallback,omitempty" yaml:"fallback,omitempty"`
+	When          string   `json:"when,omitempty" yaml:"when,omitempty"`
 }
 
 // Actionable represents an action that can be executed on a task.
@@ -92,6 +97,16 @@ func (a *Action) Validate() error {
 		}
 	}
 
+	if a.When != "" {
+		if _, err := when.Parse(a.When); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "when",
+				Value:   a.When,
+				Message: fmt.Sprintf("invalid condition: %v", err),
+			})
+		}
+	}
+
 	if len(errors) > 0 {
 		return &ValidationErrors{Errors: errors}
 	}
@@ -99,6 +114,57 @@ func (a *Action) Validate() error {
 	return nil
 }
 
+// SupportsMode reports whether the action is offered in the given mode.
+// Actions that do not list any modes are available in every mode.
+func (a *Action) SupportsMode(mode string) bool {
+	if len(a.Modes) == 0 {
+		return true
+	}
+	for _, m := range a.Modes {
+		if strings.EqualFold(strings.TrimSpace(m), mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// CaptureVariables lists the environment variables exported for the capture
+// groups of the action's regex, or nil when it has none or does not compile.
+func (a *Action) CaptureVariables() []string {
+	re, err := regexp.Compile(a.Regex)
+	if err != nil {
+		return nil
+	}
+
+	var vars []string
+	for i, name := range re.SubexpNames() {
+		if i == 0 {
+			continue
+		}
+		vars = append(vars, fmt.Sprintf("MATCH_%d", i))
+		if name != "" {
+			vars = append(vars, "MATCH_"+strings.ToUpper(name))
+		}
+	}
+	return vars
+}
+
+// MatchEnvironment returns the environment variables for the capture groups
+// of a regex match: MATCH_1..N for every group and MATCH_<NAME> for named
+// groups. Groups that did not take part in the match are empty.
+func MatchEnvironment(re *regexp.Regexp, match []string) map[string]string {
+	env := make(map[string]string, 2*len(match))
+	names := re.SubexpNames()
+
+	for i := 1; i < len(match) && i < len(names); i++ {
+		env[fmt.Sprintf("MATCH_%d", i)] = match[i]
+		if names[i] != "" {
+			env["MATCH_"+strings.ToUpper(names[i])] = match[i]
+		}
+	}
+	return env
+}
+
 // Validate performs validation on an Actionable struct.
 func (a *Actionable) Validate() error {
 	var errors []ValidationError
@@ -2,6 +2,8 @@ package types
 
 import (
 	"encoding/json"
+	"reflect"
+	"regexp"
 	"testing"
 )
 
@@ -182,6 +184,63 @@ func TestActionableValidation(t *testing.T) {
 	}
 }
 
+func TestActionSupportsMode(t *testing.T) {
+	tests := []struct {
+		name  string
+		modes []string
+		mode  string
+		want  bool
+	}{
+		{"no modes means all modes", nil, "batch", true},
+		{"listed mode", []string{"batch", "normal"}, "normal", true},
+		{"unlisted mode", []string{"batch", "normal"}, "any", false},
+		{"whitespace and case are ignored", []string{" Any "}, "any", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := Action{Name: "test", Target: "annotations", Command: "echo", Modes: tt.modes}
+			if got := action.SupportsMode(tt.mode); got != tt.want {
+				t.Errorf("SupportsMode(%q) = %v, want %v", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionCaptureVariables(t *testing.T) {
+	tests := []struct {
+		regex string
+		want  []string
+	}{
+		{`.*`, nil},
+		{`JIRA-(\d+)`, []string{"MATCH_1"}},
+		{`(?P<project>[A-Z]+)-(?P<key>\d+)`, []string{"MATCH_1", "MATCH_PROJECT", "MATCH_2", "MATCH_KEY"}},
+		{`(`, nil},
+	}
+
+	for _, tt := range tests {
+		action := Action{Regex: tt.regex}
+		if got := action.CaptureVariables(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CaptureVariables() for %q = %v, want %v", tt.regex, got, tt.want)
+		}
+	}
+}
+
+func TestMatchEnvironment(t *testing.T) {
+	re := regexp.MustCompile(`(?P<project>[A-Z]+)-(\d+)(x)?`)
+	env := MatchEnvironment(re, re.FindStringSubmatch("see JIRA-42"))
+
+	want := map[string]string{
+		"MATCH_1":       "JIRA",
+		"MATCH_PROJECT": "JIRA",
+		"MATCH_2":       "42",
+		"MATCH_3":       "",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("MatchEnvironment() = %v, want %v", env, want)
+	}
+}
+
 func TestJSONSerialization(t *testing.T) {
 	action := Action{
 		Name:          "test-action",
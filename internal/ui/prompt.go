@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/johnconnor-sec/taskopen-go/internal/output"
+)
+
+// Prompt asks for a single line of text in a full-screen prompt. Title and
+// context are shown above the input line. It returns false when the user
+// cancels with Escape or Ctrl+C.
+func Prompt(title, context, label string, accessMode output.AccessibilityMode) (string, bool, error) {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create screen: %w", err)
+	}
+	if err := screen.Init(); err != nil {
+		return "", false, fmt.Errorf("failed to initialize screen: %w", err)
+	}
+
+	// Reuse the menu styles and drawing helpers
+	t := &SecureTUI{screen: screen}
+	t.setupStyles(accessMode)
+	defer t.Close()
+
+	var input []rune
+	for {
+		t.width, t.height = screen.Size()
+		screen.Clear()
+		t.drawText(0, 0, t.width, title, t.borderStyle.Bold(true))
+		t.drawHorizontalLine(1, t.borderStyle)
+		t.drawText(0, 2, t.width, context, t.normalStyle)
+		line := label + string(input)
+		t.drawText(0, 4, t.width, line, t.searchStyle)
+		screen.ShowCursor(min(len([]rune(line)), t.width-1), 4)
+		t.drawText(0, t.height-1, t.width, "Enter: confirm | Escape: skip | Ctrl+U: clear", t.borderStyle)
+		screen.Show()
+
+		var ev *tcell.EventKey
+		switch event := screen.PollEvent().(type) {
+		case *tcell.EventResize:
+			screen.Sync()
+			continue
+		case *tcell.EventKey:
+			ev = event
+		default:
+			continue
+		}
+
+		switch ev.Key() {
+		case tcell.KeyEscape, tcell.KeyCtrlC:
+			return "", false, nil
+		case tcell.KeyEnter:
+			return string(input), true, nil
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		case tcell.KeyCtrlU:
+			input = input[:0]
+		case tcell.KeyRune:
+			if ev.Rune() >= 32 {
+				input = append(input, ev.Rune())
+			}
+		}
+	}
+}
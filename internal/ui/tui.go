@@ -52,9 +52,13 @@ type SecureTUI struct {
 	showPreview  bool
 	previewWidth int
 
+	// Multi-selection, keyed by item ID so marks survive filtering
+	marked map[string]bool
+
 	// State management
-	running bool
-	mutex   sync.RWMutex
+	running   bool
+	cancelled bool
+	mutex     sync.RWMutex
 
 	// Dimensions
 	width  int
@@ -109,6 +113,7 @@ func NewSecureTUI(items []MenuItem, config MenuConfig, tuiConfig SecureTUIConfig
 		previewWidth:    tuiConfig.PreviewWidth,
 		hideEnvVars:     tuiConfig.HideEnvVars,
 		visibilityLevel: tuiConfig.VisibilityLevel,
+		marked:          make(map[string]bool),
 		running:         false,
 		width:           width,
 		height:          height,
@@ -151,9 +156,12 @@ func (t *SecureTUI) setupStyles(accessMode output.AccessibilityMode) {
 	}
 }
 
-// Show displays the TUI and handles user interaction
-func (t *SecureTUI) Show() (*MenuItem, error) {
+// Show displays the TUI and handles user interaction. It returns the marked
+// items in their original order, or the highlighted item when none are
+// marked. A nil slice means the user cancelled.
+func (t *SecureTUI) Show() ([]MenuItem, error) {
 	t.running = true
+	t.cancelled = false
 	defer t.Close()
 
 	// Initial draw
@@ -168,11 +176,10 @@ func (t *SecureTUI) Show() (*MenuItem, error) {
 		case *tcell.EventKey:
 			if !t.handleKeyEvent(ev) {
 				// Selection made or cancelled
-				if t.selected >= 0 && t.selected < len(t.filtered) {
-					selected := t.filtered[t.selected]
-					return &selected, nil
+				if t.cancelled {
+					return nil, nil
 				}
-				return nil, nil
+				return t.selection(), nil
 			}
 		}
 		t.draw()
@@ -181,6 +188,39 @@ func (t *SecureTUI) Show() (*MenuItem, error) {
 	return nil, nil
 }
 
+// selection returns the marked items, falling back to the highlighted one
+func (t *SecureTUI) selection() []MenuItem {
+	var selected []MenuItem
+	for _, item := range t.items {
+		if t.marked[item.ID] {
+			selected = append(selected, item)
+		}
+	}
+
+	if len(selected) == 0 && t.selected >= 0 && t.selected < len(t.filtered) {
+		selected = append(selected, t.filtered[t.selected])
+	}
+	return selected
+}
+
+// toggleMark marks or unmarks the highlighted item and moves to the next one
+func (t *SecureTUI) toggleMark() {
+	if t.selected < 0 || t.selected >= len(t.filtered) {
+		return
+	}
+
+	id := t.filtered[t.selected].ID
+	if t.marked[id] {
+		delete(t.marked, id)
+	} else {
+		t.marked[id] = true
+	}
+
+	if t.selected < len(t.filtered)-1 {
+		t.selected++
+	}
+}
+
 // handleKeyEvent processes keyboard input securely
 func (t *SecureTUI) handleKeyEvent(ev *tcell.EventKey) bool {
 	t.mutex.Lock()
@@ -203,11 +243,22 @@ func (t *SecureTUI) handleInteractiveMode(ev *tcell.EventKey) bool {
 	switch ev.Key() {
 	case tcell.KeyEscape, tcell.KeyCtrlC:
 		t.running = false
+		t.cancelled = true
 		return false
 	case tcell.KeyEnter:
 		// Selection made
 		t.running = false
 		return false
+	case tcell.KeyRune:
+		if ev.Rune() == ' ' && t.config.AllowMultiSelect {
+			t.toggleMark()
+			return true
+		}
+		if ev.Rune() >= 32 && ev.Rune() < 127 {
+			t.mode = ModeSearch
+			t.searchQuery = string(ev.Rune())
+			t.performSearch()
+		}
 	case tcell.KeyUp, tcell.KeyCtrlP:
 		if t.selected > 0 {
 			t.selected--
@@ -232,14 +283,8 @@ func (t *SecureTUI) handleInteractiveMode(ev *tcell.EventKey) bool {
 		}
 	case tcell.KeyF1:
 		t.mode = ModeHelp
-	case tcell.KeyCtrlF, tcell.KeyRune:
-		if ev.Key() == tcell.KeyCtrlF || (ev.Rune() >= 32 && ev.Rune() < 127) {
-			t.mode = ModeSearch
-			if ev.Rune() >= 32 && ev.Rune() < 127 {
-				t.searchQuery = string(ev.Rune())
-				t.performSearch()
-			}
-		}
+	case tcell.KeyCtrlF:
+		t.mode = ModeSearch
 	}
 
 	return true
@@ -294,6 +339,10 @@ func (t *SecureTUI) handlePreviewMode(ev *tcell.EventKey) bool {
 		if t.selected < len(t.filtered)-1 {
 			t.selected++
 		}
+	case tcell.KeyRune:
+		if ev.Rune() == ' ' && t.config.AllowMultiSelect {
+			t.toggleMark()
+		}
 	case tcell.KeyEnter:
 		t.running = false
 		return false
@@ -407,6 +456,14 @@ func (t *SecureTUI) formatItemSecurely(item MenuItem, index int) string {
 	keyText := fmt.Sprintf("%d", index+1)
 	text := fmt.Sprintf("%-2s %s", keyText, item.Text)
 
+	if t.config.AllowMultiSelect {
+		mark := "[ ]"
+		if t.marked[item.ID] {
+			mark = "[x]"
+		}
+		text = fmt.Sprintf("%-2s %s %s", keyText, mark, item.Text)
+	}
+
 	if item.Description != "" {
 		// Sanitize description that might contain environment variables
 		safeDesc := t.sanitizeText(item.Description)
@@ -505,6 +562,14 @@ func (t *SecureTUI) drawSecurePreview(x, y, width int, item MenuItem) {
 			lines = append(lines, "", "Command:", safeCmdStr)
 		}
 
+		// Inline command output (sanitized like the description)
+		if inline, ok := data["inline"].(string); ok && inline != "" {
+			lines = append(lines, "", "Inline:")
+			for _, line := range strings.Split(inline, "\n") {
+				lines = append(lines, "  "+t.sanitizeText(line))
+			}
+		}
+
 		// Environment variables (securely displayed)
 		if !t.hideEnvVars {
 			if env, exists := data["environment"]; exists {
@@ -538,7 +603,8 @@ func (t *SecureTUI) drawHelp() {
 		"",
 		"Navigation:",
 		"  ↑/↓ or j/k      Navigate items",
-		"  Enter           Select item",
+		"  Enter           Select item (or all marked items)",
+		"  Space           Mark/unmark item for bulk execution",
 		"  Escape/Ctrl+C   Exit",
 		"  Home/End        First/Last item",
 		"",
@@ -579,6 +645,9 @@ func (t *SecureTUI) drawStatusBar() {
 	if len(t.filtered) != len(t.items) {
 		status += fmt.Sprintf(" (filtered from %d)", len(t.items))
 	}
+	if len(t.marked) > 0 {
+		status += fmt.Sprintf(" | %d marked", len(t.marked))
+	}
 
 	// Mode indicator
 	modeText := "Interactive"
@@ -0,0 +1,532 @@
+// Package when parses and evaluates the `when` conditions that restrict
+// actions to tasks with matching attributes.
+package when
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johnconnor-sec/taskopen-go/internal/taskwarrior"
+)
+
+// Condition is a parsed `when` expression that selects tasks by their
+// attributes, for example:
+//
+//	+work and project.startswith:client and (due.before:now+2d or priority:H)
+//
+// Terms are tags (+tag, -tag), attr:value with an optional modifier
+// (attr.modifier:value) and comparisons (attr <op> value). Terms combine with
+// and, or and not (also &&, || and !); adjacent terms are joined with and.
+type Condition struct {
+	root conditionNode
+}
+
+// Parse parses a `when` expression.
+func Parse(expr string) (*Condition, error) {
+	tokens, err := lexCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+
+	p := &conditionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return &Condition{root: root}, nil
+}
+
+// Matches reports whether task satisfies the condition. Relative dates such
+// as now+2d are resolved against now.
+func (c *Condition) Matches(task map[string]any, now time.Time) bool {
+	return c.root.eval(task, now)
+}
+
+// Modifiers accepted after an attribute name and the operator they map to
+var conditionModifiers = map[string]string{
+	"is":         "eq",
+	"equals":     "eq",
+	"isnt":       "ne",
+	"not":        "ne",
+	"has":        "has",
+	"contains":   "has",
+	"hasnt":      "hasnt",
+	"startswith": "startswith",
+	"left":       "startswith",
+	"endswith":   "endswith",
+	"right":      "endswith",
+	"before":     "lt",
+	"below":      "lt",
+	"under":      "lt",
+	"after":      "gt",
+	"above":      "gt",
+	"over":       "gt",
+	"any":        "any",
+	"none":       "none",
+}
+
+// Comparison operator tokens and the operator they map to
+var conditionOperators = map[string]string{
+	"=": "eq", "==": "eq", "!=": "ne",
+	"<": "lt", "<=": "le", ">": "gt", ">=": "ge",
+}
+
+var attributeNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+type conditionNode interface {
+	eval(task map[string]any, now time.Time) bool
+}
+
+type andNode struct{ left, right conditionNode }
+
+func (n andNode) eval(task map[string]any, now time.Time) bool {
+	return n.left.eval(task, now) && n.right.eval(task, now)
+}
+
+type orNode struct{ left, right conditionNode }
+
+func (n orNode) eval(task map[string]any, now time.Time) bool {
+	return n.left.eval(task, now) || n.right.eval(task, now)
+}
+
+type notNode struct{ operand conditionNode }
+
+func (n notNode) eval(task map[string]any, now time.Time) bool {
+	return !n.operand.eval(task, now)
+}
+
+// tagNode matches +tag (present) or -tag (absent)
+type tagNode struct {
+	tag     string
+	present bool
+}
+
+func (n tagNode) eval(task map[string]any, _ time.Time) bool {
+	tags, _ := task["tags"].([]any)
+	return slices.ContainsFunc(tags, func(tag any) bool { return tag == n.tag }) == n.present
+}
+
+// compareNode compares a task attribute with a value
+type compareNode struct {
+	attr  string
+	op    string
+	value string
+}
+
+func (n compareNode) eval(task map[string]any, now time.Time) bool {
+	raw, present := task[n.attr]
+	actual := conditionString(raw)
+
+	switch n.op {
+	case "any":
+		return actual != ""
+	case "none":
+		return actual == ""
+	}
+
+	// Lists such as tags and depends match by element
+	if list, ok := raw.([]any); ok {
+		has := slices.ContainsFunc(list, func(v any) bool { return conditionString(v) == n.value })
+		switch n.op {
+		case "eq", "has":
+			return has
+		case "ne", "hasnt":
+			return !has
+		}
+	}
+
+	switch n.op {
+	case "eq":
+		return actual == n.value
+	case "ne":
+		return actual != n.value
+	case "has":
+		return strings.Contains(actual, n.value)
+	case "hasnt":
+		return !strings.Contains(actual, n.value)
+	case "startswith":
+		return strings.HasPrefix(actual, n.value)
+	case "endswith":
+		return strings.HasSuffix(actual, n.value)
+	}
+
+	if !present {
+		return false
+	}
+
+	cmp := compareConditionValues(actual, n.value, now)
+	switch n.op {
+	case "lt":
+		return cmp < 0
+	case "le":
+		return cmp <= 0
+	case "gt":
+		return cmp > 0
+	case "ge":
+		return cmp >= 0
+	}
+	return false
+}
+
+// compareConditionValues compares an attribute value with a condition value
+// as dates, then as numbers, then as strings
+func compareConditionValues(actual, value string, now time.Time) int {
+	if a, ok := parseTaskDate(actual); ok {
+		if b, ok := parseDateValue(value, now); ok {
+			return a.Compare(b)
+		}
+	}
+
+	a, errA := strconv.ParseFloat(actual, 64)
+	b, errB := strconv.ParseFloat(value, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(actual, value)
+}
+
+// conditionString converts a task attribute to the string it is compared as
+func conditionString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseTaskDate parses a date as exported by Taskwarrior, e.g.
+// 20240101T120000Z, or in RFC 3339 format.
+func parseTaskDate(value string) (time.Time, bool) {
+	t, err := taskwarrior.ParseTimestamp(value)
+	return t.Time, err == nil
+}
+
+var dateOffsetRegex = regexp.MustCompile(`^([+-])(\d+)(s|min|h|d|w|mo|y)$`)
+
+// parseDateValue parses the date side of a condition: now, today, tomorrow
+// or yesterday with an optional offset such as +2d or -1w, or an absolute
+// date like 2024-01-15 or 2024-01-15T10:00:00Z
+func parseDateValue(value string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	bases := []struct {
+		name string
+		time time.Time
+	}{
+		{"now", now},
+		{"today", today},
+		{"tomorrow", today.AddDate(0, 0, 1)},
+		{"yesterday", today.AddDate(0, 0, -1)},
+	}
+
+	for _, base := range bases {
+		offset, ok := strings.CutPrefix(value, base.name)
+		if !ok || (offset != "" && offset[0] != '+' && offset[0] != '-') {
+			continue
+		}
+		if offset == "" {
+			return base.time, true
+		}
+
+		m := dateOffsetRegex.FindStringSubmatch(offset)
+		if m == nil {
+			return time.Time{}, false
+		}
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+
+		switch m[3] {
+		case "s":
+			return base.time.Add(time.Duration(n) * time.Second), true
+		case "min":
+			return base.time.Add(time.Duration(n) * time.Minute), true
+		case "h":
+			return base.time.Add(time.Duration(n) * time.Hour), true
+		case "d":
+			return base.time.AddDate(0, 0, n), true
+		case "w":
+			return base.time.AddDate(0, 0, 7*n), true
+		case "mo":
+			return base.time.AddDate(0, n, 0), true
+		default:
+			return base.time.AddDate(n, 0, 0), true
+		}
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, true
+	}
+	return parseTaskDate(value)
+}
+
+var relativeDateRegex = regexp.MustCompile(`^(now|today|tomorrow|yesterday)([+-].*)?$`)
+
+// isRelativeDate reports whether value is a relative date keyword, alone or
+// followed by an offset. Words that merely start with one, such as nowhere,
+// are not dates.
+func isRelativeDate(value string) bool {
+	return relativeDateRegex.MatchString(value)
+}
+
+type conditionTokenKind int
+
+const (
+	tokenWord conditionTokenKind = iota
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type conditionToken struct {
+	kind   conditionTokenKind
+	text   string
+	pos    int
+	quoted bool
+}
+
+// lexCondition splits a condition into words, operators and parentheses.
+// Quoted parts of a word may contain spaces and operator characters.
+func lexCondition(expr string) ([]conditionToken, error) {
+	const special = " \t\n()<>=!&|"
+	var tokens []conditionToken
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, conditionToken{kind: tokenLParen, text: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, conditionToken{kind: tokenRParen, text: ")", pos: i})
+			i++
+
+		case strings.IndexByte(special, c) >= 0:
+			n := 1
+			if i+1 < len(expr) && slices.Contains([]string{"<=", ">=", "==", "!=", "&&", "||"}, expr[i:i+2]) {
+				n = 2
+			}
+			op := expr[i : i+n]
+			if op == "&" || op == "|" {
+				return nil, fmt.Errorf("unexpected %q at position %d", op, i+1)
+			}
+			tokens = append(tokens, conditionToken{kind: tokenOperator, text: op, pos: i})
+			i += n
+
+		default:
+			start := i
+			quoted := false
+			var word strings.Builder
+			for i < len(expr) && strings.IndexByte(special, expr[i]) < 0 {
+				if q := expr[i]; q == '"' || q == '\'' {
+					end := strings.IndexByte(expr[i+1:], q)
+					if end < 0 {
+						return nil, fmt.Errorf("unclosed quote at position %d", i+1)
+					}
+					word.WriteString(expr[i+1 : i+1+end])
+					i += end + 2
+					quoted = true
+					continue
+				}
+				word.WriteByte(expr[i])
+				i++
+			}
+			tokens = append(tokens, conditionToken{kind: tokenWord, text: word.String(), pos: start, quoted: quoted})
+		}
+	}
+
+	return tokens, nil
+}
+
+// conditionParser is a recursive descent parser over condition tokens
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+func (p *conditionParser) errorf(format string, args ...any) error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf(format+" at end of condition", args...)
+	}
+	return fmt.Errorf(format+" at position %d", append(args, p.tokens[p.pos].pos+1)...)
+}
+
+// accept consumes the next token if it is one of the given keywords or
+// operators
+func (p *conditionParser) accept(keyword, operator string) bool {
+	if p.pos >= len(p.tokens) {
+		return false
+	}
+	token := p.tokens[p.pos]
+	if (token.kind == tokenWord && !token.quoted && strings.EqualFold(token.text, keyword)) ||
+		(token.kind == tokenOperator && token.text == operator) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// startsOperand reports whether the next token can begin an operand, which
+// joins it to the previous one with an implicit and
+func (p *conditionParser) startsOperand() bool {
+	if p.pos >= len(p.tokens) {
+		return false
+	}
+	token := p.tokens[p.pos]
+	switch token.kind {
+	case tokenLParen:
+		return true
+	case tokenOperator:
+		return token.text == "!"
+	case tokenWord:
+		return token.quoted || !(strings.EqualFold(token.text, "and") || strings.EqualFold(token.text, "or"))
+	}
+	return false
+}
+
+func (p *conditionParser) parseOr() (conditionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or", "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (conditionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and", "&&") || p.startsOperand() {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (conditionNode, error) {
+	if p.accept("not", "!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *conditionParser) parsePrimary() (conditionNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("expected a term")
+	}
+
+	token := p.tokens[p.pos]
+	switch token.kind {
+	case tokenLParen:
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenRParen {
+			return nil, p.errorf("expected \")\"")
+		}
+		p.pos++
+		return node, nil
+	case tokenWord:
+		p.pos++
+		return p.parseTerm(token)
+	}
+	return nil, p.errorf("unexpected %q", token.text)
+}
+
+// parseTerm parses a tag, an attr:value term or an attr <op> value comparison
+func (p *conditionParser) parseTerm(token conditionToken) (conditionNode, error) {
+	text := token.text
+
+	if !token.quoted && len(text) > 1 && (text[0] == '+' || text[0] == '-') {
+		return tagNode{tag: text[1:], present: text[0] == '+'}, nil
+	}
+
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOperator {
+		op, ok := conditionOperators[p.tokens[p.pos].text]
+		if !ok {
+			return nil, p.errorf("unexpected %q", p.tokens[p.pos].text)
+		}
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenWord {
+			return nil, p.errorf("expected a value")
+		}
+		value := p.tokens[p.pos]
+		p.pos++
+		return newCompareNode(text, op, value.text, token.pos)
+	}
+
+	name, value, ok := strings.Cut(text, ":")
+	if !ok {
+		return nil, fmt.Errorf("expected a tag, attr:value or comparison, got %q at position %d", text, token.pos+1)
+	}
+
+	op := "eq"
+	if attr, modifier, found := strings.Cut(name, "."); found {
+		if op, ok = conditionModifiers[strings.ToLower(modifier)]; !ok {
+			return nil, fmt.Errorf("unknown modifier %q at position %d", modifier, token.pos+1)
+		}
+		name = attr
+	}
+	return newCompareNode(name, op, value, token.pos)
+}
+
+// newCompareNode validates and builds a comparison
+func newCompareNode(attr, op, value string, pos int) (conditionNode, error) {
+	if !attributeNameRegex.MatchString(attr) {
+		return nil, fmt.Errorf("invalid attribute name %q at position %d", attr, pos+1)
+	}
+
+	switch op {
+	case "lt", "le", "gt", "ge":
+		if isRelativeDate(value) {
+			if _, ok := parseDateValue(value, time.Now()); !ok {
+				return nil, fmt.Errorf("invalid date %q at position %d", value, pos+1)
+			}
+		}
+	}
+
+	return compareNode{attr: attr, op: op, value: value}, nil
+}
@@ -0,0 +1,99 @@
+package when
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCondition_Matches(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	task := map[string]any{
+		"project":     "client.acme",
+		"description": "Review the spec",
+		"tags":        []any{"work", "review"},
+		"priority":    "H",
+		"urgency":     float64(8.5),
+		"due":         "20240311T090000Z",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"+work", true},
+		{"+home", false},
+		{"-home", true},
+		{"project:client.acme", true},
+		{"project.startswith:client", true},
+		{"project.startswith:home", false},
+		{"description.contains:spec", true},
+		{`description.has:"the spec"`, true},
+		{"due.before:now+2d", true},
+		{"due.before:now+12h", false},
+		{"due.after:today", true},
+		{"due < tomorrow+1d", true},
+		{"due > 2024-03-12", false},
+		{"urgency > 5", true},
+		{"urgency>=8.5 and urgency<=8.5", true},
+		{"priority != L", true},
+		{"scheduled.any:", false},
+		{"scheduled.none:", true},
+		{"scheduled:", true},
+		{"scheduled.before:now", false},
+		{"+work and priority:L", false},
+		{"+work or priority:L", true},
+		{"+work priority:H", true},
+		{"not +work", false},
+		{"!(+home || +errand) && +review", true},
+		{"(+home or +work) and not project.endswith:acme", false},
+		{"tags:review", true},
+		{"tags.hasnt:review", false},
+		{"project == nowhere", false},
+		{"project < nowhere", true},
+		{"due.before:todayish", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			condition, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if got := condition.Matches(task, now); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		message string
+	}{
+		{"", "empty condition"},
+		{"+work and", "expected a term at end of condition"},
+		{"(+work", `expected ")"`},
+		{"+work)", `unexpected ")" at position 6`},
+		{"project.beginswith:x", `unknown modifier "beginswith"`},
+		{"due.before:now+2x", `invalid date "now+2x"`},
+		{"urgency >", "expected a value"},
+		{"urgency => 5", "expected a value at position 10"},
+		{"work", `expected a tag, attr:value or comparison, got "work"`},
+		{`project:"open`, "unclosed quote"},
+		{"+a & +b", `unexpected "&"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want error", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.expr, err, tt.message)
+			}
+		})
+	}
+}